@@ -3,16 +3,22 @@ package main
 import (
 	"bufio"
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/ai"
 	"github.com/Karan2980/llm-planner-golang-project/internal/api"
+	calendarpkg "github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/cassette"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
 	"github.com/joho/godotenv"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -21,6 +27,10 @@ import (
 )
 
 func main() {
+	evalIntents := flag.String("eval-intents", "", "measure intent classification on a labeled corpus and exit")
+	rulesOnly := flag.Bool("rules-only", false, "with -eval-intents, classify with the keyword rules alone")
+	flag.Parse()
+
 	// Load environment variables from .env file
 	if err := loadEnvFile(); err != nil {
 		log.Printf("Warning: Could not load .env file: %v", err)
@@ -36,20 +46,27 @@ func main() {
 	// Load configuration from environment
 	config := loadConfig()
 
-	// Check if GitHub token is configured
-	if config.AI.GitHubToken == "" || config.AI.GitHubToken == "your_github_token_here" {
-		log.Fatal("❌ GitHub token not configured! Please add GITHUB_TOKEN to your .env file")
+	if *evalIntents != "" {
+		if err := runIntentEvaluation(*evalIntents, config, *rulesOnly); err != nil {
+			log.Fatalf("❌ Intent evaluation failed: %v", err)
+		}
+		return
+	}
+
+	// Check if at least one AI provider is configured
+	if aiManager := ai.NewManager(config.AI); !aiManager.HasClients() {
+		aiManager.PrintAvailableClients()
+		log.Fatal("❌ No AI provider configured!")
 	}
 
-	// Create Google Calendar service with interactive token setup
-	fmt.Println("🔐 Setting up Google Calendar access...")
-	calendarService, err := createCalendarServiceInteractive(ctx, config.Google)
+	// Create calendar backend
+	backend, err := createCalendarBackend(ctx, config)
 	if err != nil {
 		log.Fatalf("❌ Unable to create Calendar service: %v", err)
 	}
 
 	// Create API server
-	server := api.NewServer(calendarService, config.AI, config.Calendar.TimeZone)
+	server := api.NewServer(backend, config.AI, config.Calendar)
 
 	// Start server
 	port := getEnvOrDefault("PORT", "8080")
@@ -60,6 +77,73 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, server.Router()))
 }
 
+// runIntentEvaluation classifies a labeled corpus and prints the accuracy
+func runIntentEvaluation(path string, config models.Config, rulesOnly bool) error {
+	corpus, err := planner.LoadIntentCorpus(path)
+	if err != nil {
+		return err
+	}
+
+	var aiManager *ai.Manager
+	if !rulesOnly {
+		aiManager = ai.NewManager(config.AI)
+	}
+	classifier := planner.NewIntentClassifier(aiManager, planner.NewPromptGenerator(nil))
+
+	fmt.Printf("🧪 Evaluating intent classification on %d labeled requests from %s\n", len(corpus), path)
+	report := classifier.Evaluate(corpus, rulesOnly)
+	report.PrintReport()
+	return nil
+}
+
+// createCalendarBackend creates the calendar backend selected in the
+// configuration, wrapped for recording or replaced by a replay when a
+// cassette is configured
+func createCalendarBackend(ctx context.Context, config models.Config) (calendarpkg.CalendarBackend, error) {
+	recording := config.Calendar.Cassette
+	if recording.Mode == "" {
+		return createBackend(ctx, config)
+	}
+
+	c, err := cassette.Open(recording.Path, recording.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if c.Replaying() {
+		fmt.Printf("📼 Replaying calendar and AI traffic from %s\n", recording.Path)
+		return calendarpkg.NewCassetteBackend(nil, c), nil
+	}
+
+	backend, err := createBackend(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("📼 Recording calendar and AI traffic to %s\n", recording.Path)
+	return calendarpkg.NewCassetteBackend(backend, c), nil
+}
+
+// createBackend creates the calendar backend selected in the configuration
+func createBackend(ctx context.Context, config models.Config) (calendarpkg.CalendarBackend, error) {
+	switch strings.ToLower(config.Calendar.Backend) {
+	case "memory":
+		fmt.Println("🧪 Using in-memory calendar backend (no Google account needed)")
+		return calendarpkg.NewMemoryBackend(), nil
+	case "", "google":
+		// Create Google Calendar service with interactive token setup
+		fmt.Println("🔐 Setting up Google Calendar access...")
+		calendarService, err := createCalendarServiceInteractive(ctx, config.Google)
+		if err != nil {
+			return nil, err
+		}
+		return calendarpkg.NewGoogleBackend(calendarService), nil
+	case "caldav":
+		fmt.Printf("📡 Using CalDAV calendar backend at %s\n", config.CalDAV.URL)
+		return calendarpkg.NewCalDAVBackend(config.CalDAV, nil)
+	default:
+		return nil, fmt.Errorf("unknown calendar backend %q. Use 'google', 'caldav' or 'memory'", config.Calendar.Backend)
+	}
+}
+
 // createCalendarServiceInteractive creates calendar service with interactive token setup
 func createCalendarServiceInteractive(ctx context.Context, config models.GoogleConfig) (*calendar.Service, error) {
 	// Check if all required credentials are present
@@ -238,12 +322,51 @@ func changeToProjectRoot() error {
 
 // loadConfig loads configuration from environment variables
 func loadConfig() models.Config {
+	recording := models.CassetteConfig{
+		Mode: os.Getenv("CASSETTE_MODE"),
+		Path: getEnvOrDefault("CASSETTE_PATH", "cassettes/session.json"),
+	}
+
 	return models.Config{
 		AI: models.AIConfig{
 			GitHubToken: os.Getenv("GITHUB_TOKEN"),
+			Providers:   splitEnvList("AI_PROVIDERS"),
+			GitHub: models.LLMProviderConfig{
+				Models: splitEnvList("GITHUB_MODELS"),
+			},
+			OpenAI: models.LLMProviderConfig{
+				APIKey:  os.Getenv("OPENAI_API_KEY"),
+				BaseURL: os.Getenv("OPENAI_BASE_URL"),
+				Models:  splitEnvList("OPENAI_MODELS"),
+			},
+			Anthropic: models.LLMProviderConfig{
+				APIKey:  os.Getenv("ANTHROPIC_API_KEY"),
+				BaseURL: os.Getenv("ANTHROPIC_BASE_URL"),
+				Models:  splitEnvList("ANTHROPIC_MODELS"),
+			},
+			Ollama: models.LLMProviderConfig{
+				BaseURL: os.Getenv("OLLAMA_BASE_URL"),
+				Models:  splitEnvList("OLLAMA_MODELS"),
+			},
+			FakeFixturePath: os.Getenv("AI_FAKE_FIXTURES"),
+			Cassette:        recording,
 		},
 		Calendar: models.CalendarConfig{
-			TimeZone: getEnvOrDefault("APP_TIMEZONE", "Asia/Kolkata"),
+			TimeZone:              getEnvOrDefault("APP_TIMEZONE", "Asia/Kolkata"),
+			Backend:               getEnvOrDefault("CALENDAR_BACKEND", "google"),
+			CalendarID:            getEnvOrDefault("CALENDAR_ID", "primary"),
+			AdditionalCalendarIDs: splitEnvList("CALENDAR_IDS"),
+			JournalPath:           getEnvOrDefault("UNDO_JOURNAL_PATH", "undo_journal.json"),
+			AvailabilityPath:      getEnvOrDefault("AVAILABILITY_PATH", "availability.json"),
+			ConflictPolicyPath:    getEnvOrDefault("CONFLICT_POLICY_PATH", "conflict_policy.json"),
+			Contacts:              splitEnvContacts("CONTACTS"),
+			SendUpdates:           getEnvOrDefault("SEND_UPDATES", models.SendUpdatesAll),
+			Cassette:              recording,
+			SlotPreferences: models.SlotPreferences{
+				BufferMinutes:  getEnvInt("SLOT_BUFFER_MINUTES", 10),
+				PreferredStart: os.Getenv("PREFERRED_HOURS_START"),
+				PreferredEnd:   os.Getenv("PREFERRED_HOURS_END"),
+			},
 		},
 		Google: models.GoogleConfig{
 			ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
@@ -253,6 +376,12 @@ func loadConfig() models.Config {
 			RefreshToken: os.Getenv("GOOGLE_REFRESH_TOKEN"),
 			TokenExpiry:  os.Getenv("GOOGLE_TOKEN_EXPIRY"),
 		},
+		CalDAV: models.CalDAVConfig{
+			URL:      os.Getenv("CALDAV_URL"),
+			Username: os.Getenv("CALDAV_USERNAME"),
+			Password: os.Getenv("CALDAV_PASSWORD"),
+			Calendar: getEnvOrDefault("CALDAV_CALENDAR", "personal"),
+		},
 	}
 }
 
@@ -263,3 +392,39 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt returns an integer environment variable or the default value
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// splitEnvList returns a comma-separated environment variable as a list
+func splitEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// splitEnvContacts reads a comma-separated list of name=email pairs, keyed
+// by lowercase name
+func splitEnvContacts(key string) map[string]string {
+	contacts := make(map[string]string)
+	for _, pair := range splitEnvList(key) {
+		name, email, ok := strings.Cut(pair, "=")
+		name, email = strings.TrimSpace(name), strings.TrimSpace(email)
+		if !ok || name == "" || email == "" {
+			log.Printf("Warning: ignoring contact %q in %s, expected name=email", pair, key)
+			continue
+		}
+		contacts[strings.ToLower(name)] = email
+	}
+	return contacts
+}
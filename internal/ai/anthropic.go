@@ -0,0 +1,299 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Anthropic API defaults
+const (
+	defaultAnthropicURL = "https://api.anthropic.com"
+	anthropicVersion    = "2023-06-01"
+)
+
+var defaultAnthropicModels = []string{"claude-sonnet-4-5", "claude-haiku-4-5"}
+
+// AnthropicClient handles the Anthropic Messages API
+type AnthropicClient struct {
+	baseURL string
+	apiKey  string
+	models  []string
+}
+
+// NewAnthropicClient creates a new Anthropic client. Empty baseURL or models
+// uses the defaults.
+func NewAnthropicClient(baseURL, apiKey string, models []string) *AnthropicClient {
+	if baseURL == "" {
+		baseURL = defaultAnthropicURL
+	}
+	return &AnthropicClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		models:  modelsOrDefault(models, defaultAnthropicModels),
+	}
+}
+
+// GetName returns the client name
+func (a *AnthropicClient) GetName() string {
+	return fmt.Sprintf("Anthropic (%s)", strings.Join(a.models, ", "))
+}
+
+// GeneratePlan generates a plan with the first model that answers
+func (a *AnthropicClient) GeneratePlan(prompt string) (string, error) {
+	var lastError error
+	for _, model := range a.models {
+		result, err := a.makeMessagesRequest(prompt, model)
+		if err == nil {
+			return result, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+	}
+
+	return "", fmt.Errorf("all Anthropic models failed, last error: %v", lastError)
+}
+
+// StreamPlan streams a plan from the first model that answers. Later models
+// are only tried while nothing has been delivered.
+func (a *AnthropicClient) StreamPlan(prompt string, onDelta func(string)) (string, error) {
+	var lastError error
+	for _, model := range a.models {
+		delivered := false
+		result, err := a.makeStreamingMessagesRequest(prompt, model, func(delta string) {
+			delivered = true
+			onDelta(delta)
+		})
+		if err == nil {
+			return result, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+		if delivered {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("all Anthropic models failed, last error: %v", lastError)
+}
+
+// CallTools asks the first model that answers which tools to call
+func (a *AnthropicClient) CallTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	var lastError error
+	for _, model := range a.models {
+		calls, err := a.makeToolRequest(prompt, model, tools)
+		if err == nil {
+			return calls, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+	}
+
+	return nil, fmt.Errorf("all Anthropic models failed, last error: %v", lastError)
+}
+
+// anthropicResponse is the part of a Messages API response we read
+type anthropicResponse struct {
+	Content []struct {
+		Type  string          `json:"type"` // "text" or "tool_use"
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+}
+
+func (a *AnthropicClient) makeMessagesRequest(prompt, model string) (string, error) {
+	reqBody := map[string]interface{}{
+		"model":  model,
+		"system": systemPrompt,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  4096,
+		"temperature": 1.0,
+	}
+
+	result, err := a.postMessages(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no text in response")
+	}
+
+	return text.String(), nil
+}
+
+// makeStreamingMessagesRequest requests a plan with "stream": true and
+// passes each text delta to onDelta
+func (a *AnthropicClient) makeStreamingMessagesRequest(prompt, model string, onDelta func(string)) (string, error) {
+	reqBody := map[string]interface{}{
+		"model":  model,
+		"system": systemPrompt,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  4096,
+		"temperature": 1.0,
+		"stream":      true,
+	}
+
+	req, err := a.newMessagesRequest(reqBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("network error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("Anthropic API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var text strings.Builder
+	err = readServerSentEvents(resp.Body, func(event, data string) (bool, error) {
+		var streamEvent struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &streamEvent); err != nil {
+			return false, fmt.Errorf("failed to parse stream event: %v", err)
+		}
+
+		switch streamEvent.Type {
+		case "content_block_delta":
+			if streamEvent.Delta.Type == "text_delta" && streamEvent.Delta.Text != "" {
+				text.WriteString(streamEvent.Delta.Text)
+				onDelta(streamEvent.Delta.Text)
+			}
+		case "message_stop":
+			return true, nil
+		case "error":
+			return false, fmt.Errorf("Anthropic API error: %s", streamEvent.Error.Message)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no text in stream")
+	}
+
+	return text.String(), nil
+}
+
+func (a *AnthropicClient) makeToolRequest(prompt, model string, tools []Tool) ([]ToolCall, error) {
+	var definitions []map[string]interface{}
+	for _, tool := range tools {
+		definitions = append(definitions, map[string]interface{}{
+			"name":         tool.Name,
+			"description":  tool.Description,
+			"input_schema": tool.Parameters,
+		})
+	}
+
+	reqBody := map[string]interface{}{
+		"model":  model,
+		"system": toolSystemPrompt,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"tools":       definitions,
+		"tool_choice": map[string]interface{}{"type": "any"},
+		"max_tokens":  4096,
+		"temperature": 0.0,
+	}
+
+	result, err := a.postMessages(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []ToolCall
+	for _, block := range result.Content {
+		if block.Type == "tool_use" {
+			calls = append(calls, ToolCall{Name: block.Name, Arguments: block.Input})
+		}
+	}
+
+	return calls, nil
+}
+
+// postMessages sends a Messages API request
+func (a *AnthropicClient) postMessages(reqBody map[string]interface{}) (*anthropicResponse, error) {
+	req, err := a.newMessagesRequest(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Anthropic API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result anthropicResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return &result, nil
+}
+
+// newMessagesRequest builds a Messages API request
+func (a *AnthropicClient) newMessagesRequest(reqBody map[string]interface{}) (*http.Request, error) {
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", a.baseURL+"/v1/messages", bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	req.Header.Set("User-Agent", "LLM-Planner-Go/1.0")
+	return req, nil
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/cassette"
+)
+
+// promptTimestampPattern matches the dates and times prompts embed, which
+// differ between recording and replay
+var promptTimestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:[ T]\d{2}:\d{2}(?::\d{2})?)?`)
+
+// planRequest is how a GeneratePlan call is stored in a cassette
+type planRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+// ReplayClient serves recorded GeneratePlan responses from a cassette, in
+// the order they were recorded
+type ReplayClient struct {
+	cassette *cassette.Cassette
+}
+
+// NewReplayClient creates a client replaying the cassette
+func NewReplayClient(c *cassette.Cassette) *ReplayClient {
+	return &ReplayClient{cassette: c}
+}
+
+// GetName returns the client name
+func (r *ReplayClient) GetName() string {
+	return fmt.Sprintf("Cassette replay (%s)", r.cassette.Path())
+}
+
+// GeneratePlan returns the next recorded response. A prompt that differs
+// from the recorded one other than in its dates and times is reported, since
+// the recorded answer may no longer fit it; the recorded response is still
+// returned so the rest of the session can be checked.
+func (r *ReplayClient) GeneratePlan(prompt string) (string, error) {
+	interaction, err := r.cassette.Next(cassette.KindLLM, "GeneratePlan")
+	if err != nil {
+		return "", err
+	}
+	r.checkPrompt(interaction, prompt)
+
+	var response string
+	err = interaction.Decode(&response)
+	return response, err
+}
+
+// StreamPlan delivers the next recorded response in one piece. Streamed and
+// non-streamed plans are recorded alike.
+func (r *ReplayClient) StreamPlan(prompt string, onDelta func(string)) (string, error) {
+	response, err := r.GeneratePlan(prompt)
+	if err != nil {
+		return "", err
+	}
+	onDelta(response)
+	return response, nil
+}
+
+// CallTools returns the next recorded tool calls
+func (r *ReplayClient) CallTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	interaction, err := r.cassette.Next(cassette.KindLLM, "CallTools")
+	if err != nil {
+		return nil, err
+	}
+	r.checkPrompt(interaction, prompt)
+
+	var calls []ToolCall
+	err = interaction.Decode(&calls)
+	return calls, err
+}
+
+// checkPrompt reports a prompt that differs from the recorded one
+func (r *ReplayClient) checkPrompt(interaction *cassette.Interaction, prompt string) {
+	var recorded planRequest
+	if err := interaction.DecodeRequest(&recorded); err == nil && !samePrompt(recorded.Prompt, prompt) {
+		fmt.Printf("⚠️ Prompt differs from the one recorded at %s\n", interaction.Time.Format("2006-01-02 15:04:05"))
+	}
+}
+
+// samePrompt compares prompts ignoring their dates and times
+func samePrompt(a, b string) bool {
+	return promptTimestampPattern.ReplaceAllString(a, "#") == promptTimestampPattern.ReplaceAllString(b, "#")
+}
+
+// recordPlan stores a GeneratePlan call in the manager's cassette
+func (m *Manager) recordPlan(prompt, response string, err error) {
+	var result interface{}
+	if err == nil {
+		result = response
+	}
+	if recordErr := m.cassette.Record(cassette.KindLLM, "GeneratePlan", planRequest{Prompt: prompt}, result, err); recordErr != nil {
+		fmt.Printf("⚠️ Failed to record AI response: %v\n", recordErr)
+	}
+}
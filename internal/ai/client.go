@@ -2,7 +2,9 @@ package ai
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/cassette"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 )
 
@@ -14,42 +16,141 @@ type Client interface {
 
 // Manager handles multiple AI clients
 type Manager struct {
-	clients []Client
+	clients  []Client
+	cassette *cassette.Cassette // records or replays every plan, if set
 }
 
-// NewManager creates a new AI manager - GitHub Models only
+// Provider names accepted in AIConfig.Providers
+const (
+	ProviderGitHub    = "github"
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+	ProviderFake      = "fake"
+)
+
+// errNoProvider is returned when no AI provider is configured
+var errNoProvider = fmt.Errorf("no AI provider configured. Please add GITHUB_TOKEN, OPENAI_API_KEY or ANTHROPIC_API_KEY to your .env file, or set AI_PROVIDERS=ollama")
+
+// defaultProviders are tried when none are configured; those without an API key are skipped
+var defaultProviders = []string{ProviderGitHub, ProviderOpenAI, ProviderAnthropic}
+
+// NewManager creates a new AI manager with the configured providers, in order
 func NewManager(config models.AIConfig) *Manager {
 	var clients []Client
-	
-	// Only add GitHub Models if token is available
-	if config.GitHubToken != "" && config.GitHubToken != "your_github_token_here" {
-		clients = append(clients, NewGitHubClient(config.GitHubToken))
+
+	// A replayed session needs no provider at all
+	var recorder *cassette.Cassette
+	if config.Cassette.Mode != "" {
+		c, err := cassette.Open(config.Cassette.Path, config.Cassette.Mode)
+		if err != nil {
+			fmt.Printf("⚠️ AI cassette disabled: %v\n", err)
+		} else if c.Replaying() {
+			return &Manager{clients: []Client{NewReplayClient(c)}}
+		} else {
+			recorder = c
+		}
 	}
-	
-	return &Manager{clients: clients}
+
+	providers := config.Providers
+	explicit := len(providers) > 0
+	if !explicit {
+		providers = defaultProviders
+	}
+
+	for _, provider := range providers {
+		client, err := newProviderClient(strings.ToLower(strings.TrimSpace(provider)), config)
+		if err != nil {
+			if explicit {
+				fmt.Printf("⚠️ Skipping AI provider %s: %v\n", provider, err)
+			}
+			continue
+		}
+		clients = append(clients, client)
+	}
+
+	return &Manager{clients: clients, cassette: recorder}
 }
 
-// GeneratePlan tries AI clients in order
-// GeneratePlan tries AI clients in order
-// GeneratePlan tries AI clients in order
+// newProviderClient creates the client for one provider
+func newProviderClient(provider string, config models.AIConfig) (Client, error) {
+	switch provider {
+	case ProviderGitHub:
+		token := config.GitHub.APIKey
+		if token == "" {
+			token = config.GitHubToken
+		}
+		if token == "" || token == "your_github_token_here" {
+			return nil, fmt.Errorf("GITHUB_TOKEN is not set")
+		}
+		return NewGitHubClient(token, config.GitHub.Models), nil
+
+	case ProviderOpenAI:
+		// OpenAI-compatible servers other than OpenAI may not need a key
+		if config.OpenAI.APIKey == "" && config.OpenAI.BaseURL == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is not set")
+		}
+		baseURL := config.OpenAI.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIURL
+		}
+		return NewOpenAIClient("OpenAI", baseURL, config.OpenAI.APIKey, modelsOrDefault(config.OpenAI.Models, defaultOpenAIModels)), nil
+
+	case ProviderAnthropic:
+		if config.Anthropic.APIKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+		}
+		return NewAnthropicClient(config.Anthropic.BaseURL, config.Anthropic.APIKey, config.Anthropic.Models), nil
+
+	case ProviderOllama:
+		return NewOllamaClient(config.Ollama.BaseURL, config.Ollama.Models), nil
+
+	case ProviderFake:
+		if config.FakeFixturePath == "" {
+			return nil, fmt.Errorf("AI_FAKE_FIXTURES is not set")
+		}
+		return LoadFakeClient(config.FakeFixturePath)
+	}
+
+	return nil, fmt.Errorf("unknown provider %q. Use 'github', 'openai', 'anthropic', 'ollama' or 'fake'", provider)
+}
+
+// modelsOrDefault returns models, or defaults when none are configured
+func modelsOrDefault(models, defaults []string) []string {
+	if len(models) == 0 {
+		return defaults
+	}
+	return models
+}
+
+// GeneratePlan tries AI clients in order, recording the outcome when a
+// cassette is being recorded
 func (m *Manager) GeneratePlan(prompt string) (string, error) {
+	result, err := m.generatePlan(prompt)
+	if m.cassette != nil {
+		m.recordPlan(prompt, result, err)
+	}
+	return result, err
+}
+
+// generatePlan returns the plan of the first AI client that answers
+func (m *Manager) generatePlan(prompt string) (string, error) {
 	if len(m.clients) == 0 {
-		return "", fmt.Errorf("no GitHub token configured. Please add GITHUB_TOKEN to your .env file")
+		return "", errNoProvider
 	}
 
-	var lastError error
-	for _, client := range m.clients { // Remove unused 'i' variable
+	var failures []string
+	for _, client := range m.clients {
 		result, err := client.GeneratePlan(prompt)
 		if err == nil {
 			return result, nil
 		}
-		
-		lastError = err
+
+		failures = append(failures, fmt.Sprintf("%s: %v", client.GetName(), err))
 	}
-	
-	return "", fmt.Errorf("GitHub Models failed: %v", lastError)
-}
 
+	return "", fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
+}
 
 // GetAvailableClients returns list of available clients
 func (m *Manager) GetAvailableClients() []string {
@@ -73,9 +174,12 @@ func (m *Manager) GetClientCount() int {
 // PrintAvailableClients prints the list of available AI clients
 func (m *Manager) PrintAvailableClients() {
 	if len(m.clients) == 0 {
-		fmt.Println("❌ No GitHub token configured!")
-		fmt.Println("💡 Please add your GitHub token to .env file:")
+		fmt.Println("❌ No AI provider configured!")
+		fmt.Println("💡 Please add at least one provider to your .env file:")
 		fmt.Println("   GITHUB_TOKEN=your_actual_github_token")
+		fmt.Println("   OPENAI_API_KEY=your_openai_api_key")
+		fmt.Println("   ANTHROPIC_API_KEY=your_anthropic_api_key")
+		fmt.Println("   AI_PROVIDERS=ollama   (local Ollama or llama.cpp server)")
 		fmt.Println("")
 		fmt.Println("🔗 Get your GitHub token from:")
 		fmt.Println("   https://github.com/settings/tokens")
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FakeFixture is a script of canned LLM responses, stored as JSON
+type FakeFixture struct {
+	Responses []FakeResponse `json:"responses"`
+	// Default is returned when no pattern matches; empty makes GeneratePlan fail
+	Default string `json:"default,omitempty"`
+}
+
+// FakeResponse is returned for prompts matching Pattern, a regular expression.
+// For tool-calling prompts the response is a JSON array of tool calls.
+// The response may refer to the pattern's capture groups as $1 or ${name},
+// e.g. to reuse the date from the prompt's "Current time: (\d{4}-\d{2}-\d{2})".
+type FakeResponse struct {
+	Name     string `json:"name,omitempty"`
+	Pattern  string `json:"pattern"`
+	Response string `json:"response"`
+}
+
+// fakeRule is a compiled FakeResponse
+type fakeRule struct {
+	name     string
+	pattern  *regexp.Regexp
+	response string
+}
+
+// FakeClient replays scripted responses instead of calling a model, so
+// plans are reproducible and no network access is needed
+type FakeClient struct {
+	rules        []fakeRule
+	defaultReply string
+}
+
+// NewFakeClient creates a fake client from a fixture. Responses are tried in
+// order and the first matching pattern wins.
+func NewFakeClient(fixture FakeFixture) (*FakeClient, error) {
+	client := &FakeClient{defaultReply: fixture.Default}
+	for i, response := range fixture.Responses {
+		pattern, err := regexp.Compile(response.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in fake response %d: %v", i+1, err)
+		}
+		name := response.Name
+		if name == "" {
+			name = fmt.Sprintf("response %d", i+1)
+		}
+		client.rules = append(client.rules, fakeRule{name: name, pattern: pattern, response: response.Response})
+	}
+	return client, nil
+}
+
+// LoadFakeClient creates a fake client from a JSON fixture file
+func LoadFakeClient(path string) (*FakeClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fake LLM fixture: %v", err)
+	}
+
+	var fixture FakeFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("unable to parse fake LLM fixture %s: %v", path, err)
+	}
+	return NewFakeClient(fixture)
+}
+
+// GetName returns the client name
+func (f *FakeClient) GetName() string {
+	return fmt.Sprintf("Fake (%d scripted responses)", len(f.rules))
+}
+
+// GeneratePlan returns the response of the first pattern matching the prompt
+func (f *FakeClient) GeneratePlan(prompt string) (string, error) {
+	return f.reply(prompt)
+}
+
+// StreamPlan delivers the scripted response word by word
+func (f *FakeClient) StreamPlan(prompt string, onDelta func(string)) (string, error) {
+	response, err := f.reply(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	for _, word := range strings.SplitAfter(response, " ") {
+		onDelta(word)
+	}
+	return response, nil
+}
+
+// CallTools returns the tool calls scripted for the prompt, a JSON array of
+// {"name": ..., "arguments": {...}} objects
+func (f *FakeClient) CallTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	response, err := f.reply(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []ToolCall
+	if err := json.Unmarshal([]byte(response), &calls); err != nil {
+		return nil, fmt.Errorf("scripted response is not a list of tool calls: %v", err)
+	}
+	return calls, nil
+}
+
+// reply returns the response of the first pattern matching the prompt
+func (f *FakeClient) reply(prompt string) (string, error) {
+	for _, rule := range f.rules {
+		match := rule.pattern.FindStringSubmatchIndex(prompt)
+		if match == nil {
+			continue
+		}
+		fmt.Printf("🧪 Fake LLM replying with %s\n", rule.name)
+		return string(rule.pattern.ExpandString(nil, rule.response, prompt, match)), nil
+	}
+
+	if f.defaultReply != "" {
+		return f.defaultReply, nil
+	}
+	return "", fmt.Errorf("no scripted response matches the prompt")
+}
@@ -0,0 +1,18 @@
+package ai
+
+// Ollama defaults. llama.cpp's llama-server exposes the same OpenAI-compatible
+// API, so it can be used by pointing the base URL at it (e.g. http://localhost:8081/v1).
+const (
+	defaultOllamaURL = "http://localhost:11434/v1"
+)
+
+var defaultOllamaModels = []string{"llama3.1"}
+
+// NewOllamaClient creates a client for a local Ollama or llama.cpp server.
+// Empty baseURL or models uses the defaults.
+func NewOllamaClient(baseURL string, models []string) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	return NewOpenAIClient("Ollama", baseURL, "", modelsOrDefault(models, defaultOllamaModels))
+}
@@ -0,0 +1,335 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI API defaults
+const (
+	defaultOpenAIURL = "https://api.openai.com/v1"
+)
+
+var defaultOpenAIModels = []string{"gpt-4o", "gpt-4o-mini"}
+
+// systemPrompt is sent with every plan request
+const systemPrompt = "You are a helpful personal assistant that creates daily schedules. Always respond with valid JSON format containing an array of tasks with summary, start, and end fields in ISO 8601 format."
+
+// requestTimeout bounds a single model request
+const requestTimeout = 60 * time.Second
+
+// OpenAIClient handles any OpenAI-compatible chat completions API: OpenAI
+// itself, GitHub Models, Ollama and llama.cpp servers
+type OpenAIClient struct {
+	name    string
+	baseURL string
+	apiKey  string
+	models  []string
+}
+
+// NewOpenAIClient creates a client for the chat completions API at baseURL.
+// Models are tried in order; apiKey may be empty for local servers.
+func NewOpenAIClient(name, baseURL, apiKey string, models []string) *OpenAIClient {
+	return &OpenAIClient{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		models:  models,
+	}
+}
+
+// GetName returns the client name
+func (o *OpenAIClient) GetName() string {
+	return fmt.Sprintf("%s (%s)", o.name, strings.Join(o.models, ", "))
+}
+
+// GeneratePlan generates a plan with the first model that answers
+func (o *OpenAIClient) GeneratePlan(prompt string) (string, error) {
+	url := o.baseURL + "/chat/completions"
+
+	var lastError error
+	for _, model := range o.models {
+		result, err := makeChatCompletionRequest(o.name, url, o.apiKey, prompt, model)
+		if err == nil {
+			return result, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+	}
+
+	return "", fmt.Errorf("all %s models failed, last error: %v", o.name, lastError)
+}
+
+// StreamPlan streams a plan from the first model that answers. Later models
+// are only tried while nothing has been delivered.
+func (o *OpenAIClient) StreamPlan(prompt string, onDelta func(string)) (string, error) {
+	url := o.baseURL + "/chat/completions"
+
+	var lastError error
+	for _, model := range o.models {
+		delivered := false
+		result, err := makeStreamingChatCompletionRequest(o.name, url, o.apiKey, prompt, model, func(delta string) {
+			delivered = true
+			onDelta(delta)
+		})
+		if err == nil {
+			return result, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+		if delivered {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("all %s models failed, last error: %v", o.name, lastError)
+}
+
+// CallTools asks the first model that answers which tools to call
+func (o *OpenAIClient) CallTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	url := o.baseURL + "/chat/completions"
+
+	var lastError error
+	for _, model := range o.models {
+		calls, err := makeChatCompletionToolRequest(o.name, url, o.apiKey, prompt, model, tools)
+		if err == nil {
+			return calls, nil
+		}
+		lastError = fmt.Errorf("%s: %v", model, err)
+	}
+
+	return nil, fmt.Errorf("all %s models failed, last error: %v", o.name, lastError)
+}
+
+func makeChatCompletionRequest(name, url, apiKey, prompt, model string) (string, error) {
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "system",
+				"content": systemPrompt,
+			},
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  4096,
+		"temperature": 1.0,
+		"stream":      false,
+	}
+
+	message, err := postChatCompletion(name, url, apiKey, reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	text, ok := message["content"].(string)
+	if !ok {
+		return "", fmt.Errorf("no content in message")
+	}
+
+	return text, nil
+}
+
+// makeStreamingChatCompletionRequest requests a plan with "stream": true and
+// passes each content delta to onDelta
+func makeStreamingChatCompletionRequest(name, url, apiKey, prompt, model string, onDelta func(string)) (string, error) {
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "system",
+				"content": systemPrompt,
+			},
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens":  4096,
+		"temperature": 1.0,
+		"stream":      true,
+	}
+
+	req, err := newChatCompletionRequest(url, apiKey, reqBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("network error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("%s API returned status %d: %s", name, resp.StatusCode, string(body))
+	}
+
+	var text strings.Builder
+	err = readServerSentEvents(resp.Body, func(event, data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, fmt.Errorf("failed to parse stream chunk: %v", err)
+		}
+		if chunk.Error != nil {
+			return false, fmt.Errorf("%s API error: %s", name, chunk.Error.Message)
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+			onDelta(chunk.Choices[0].Delta.Content)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no content in stream")
+	}
+
+	return text.String(), nil
+}
+
+func makeChatCompletionToolRequest(name, url, apiKey, prompt, model string, tools []Tool) ([]ToolCall, error) {
+	var functions []map[string]interface{}
+	for _, tool := range tools {
+		functions = append(functions, map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters":  tool.Parameters,
+			},
+		})
+	}
+
+	reqBody := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]interface{}{
+			{
+				"role":    "system",
+				"content": toolSystemPrompt,
+			},
+			{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"tools":       functions,
+		"tool_choice": "auto",
+		"max_tokens":  4096,
+		"temperature": 0.0,
+		"stream":      false,
+	}
+
+	message, err := postChatCompletion(name, url, apiKey, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	toolCalls, _ := message["tool_calls"].([]interface{})
+	var calls []ToolCall
+	for _, item := range toolCalls {
+		toolCall, _ := item.(map[string]interface{})
+		function, ok := toolCall["function"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("no function in tool call")
+		}
+		name, _ := function["name"].(string)
+		arguments, _ := function["arguments"].(string) // a JSON-encoded string
+		if arguments == "" {
+			arguments = "{}"
+		}
+		calls = append(calls, ToolCall{Name: name, Arguments: json.RawMessage(arguments)})
+	}
+
+	return calls, nil
+}
+
+// postChatCompletion sends a chat completions request and returns the
+// message of the first choice
+func postChatCompletion(name, url, apiKey string, reqBody map[string]interface{}) (map[string]interface{}, error) {
+	req, err := newChatCompletionRequest(url, apiKey, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s API returned status %d: %s", name, resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	choices, ok := result["choices"].([]interface{})
+	if !ok || len(choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	choice, ok := choices[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid choice in response")
+	}
+	message, ok := choice["message"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no message in choice")
+	}
+
+	return message, nil
+}
+
+// newChatCompletionRequest builds a chat completions request
+func newChatCompletionRequest(url, apiKey string, reqBody map[string]interface{}) (*http.Request, error) {
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("User-Agent", "LLM-Planner-Go/1.0")
+	return req, nil
+}
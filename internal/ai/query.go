@@ -55,6 +55,13 @@ func (q *QueryProcessor) processRuleBasedQuery(question string, context models.Q
 		return response
 	}
 
+	// Who has replied to an invitation
+	if q.isRSVPQuery(question) {
+		response := q.findRSVPs(question, context)
+		response.Action = "view"
+		return response
+	}
+
 	// Events for tomorrow or a specific day
 	if strings.Contains(question, "tomorrow") || strings.Contains(question, "next day") || strings.Contains(question, "day after tomorrow") {
 		response := q.getEventsForSpecificDay(question, context)
@@ -515,6 +522,11 @@ func (q *QueryProcessor) processEnhancedRuleBasedQuery(question string, context
 func (q *QueryProcessor) processViewQuery(question string, context models.QueryContext) *models.QueryResponse {
 	question = strings.ToLower(strings.TrimSpace(question))
 
+	// Who has replied to an invitation
+	if q.isRSVPQuery(question) {
+		return q.findRSVPs(question, context)
+	}
+
 	// Events for tomorrow or a specific day
 	if strings.Contains(question, "tomorrow") || strings.Contains(question, "next day") || strings.Contains(question, "day after tomorrow") {
 		return q.getEventsForSpecificDay(question, context)
@@ -563,31 +575,44 @@ func (q *QueryProcessor) processViewQuery(question string, context models.QueryC
 // createUnifiedPrompt creates a prompt for AI processing
 func (q *QueryProcessor) createUnifiedPrompt(question string, context models.QueryContext) string {
 	currentTime := context.CurrentTime.Format("2006-01-02 15:04:05")
+	timeZone := context.CurrentTime.Location().String()
+	if context.TimeZone != "" {
+		timeZone = context.TimeZone
+	}
+	offset := context.CurrentTime.Format("-07:00")
 	
 	// Create a summary of current events
 	var eventSummary string
 	if len(context.TodaysEvents) > 0 {
 		eventSummary += fmt.Sprintf("Today's events (%d):\n", len(context.TodaysEvents))
 		for _, event := range context.TodaysEvents {
-			eventSummary += fmt.Sprintf("- %s at %s\n", event.Summary, utils.FormatTime(event.Start))
+			eventSummary += fmt.Sprintf("- %s at %s%s\n", event.Summary, utils.FormatTime(event.Start), utils.ReminderNote(event))
 		}
 	}
 	
 	if len(context.UpcomingEvents) > 0 {
 		eventSummary += fmt.Sprintf("Upcoming events (%d):\n", len(context.UpcomingEvents))
 		for _, event := range context.UpcomingEvents {
-			eventSummary += fmt.Sprintf("- %s at %s\n", event.Summary, utils.FormatTime(event.Start))
+			eventSummary += fmt.Sprintf("- %s at %s%s\n", event.Summary, utils.FormatTime(event.Start), utils.ReminderNote(event))
 		}
 	}
 	
 	if eventSummary == "" {
 		eventSummary = "No events scheduled."
 	}
+	if context.Availability != nil {
+		eventSummary += "\nAvailability (create events inside the working hours unless the user asks for a specific time):\n" +
+			context.Availability.Describe(context.CurrentTime) + "\n"
+	}
+	if conversation := utils.FormatConversation(context.Conversation); conversation != "" {
+		eventSummary += "\n" + conversation
+	}
 
 	prompt := fmt.Sprintf(`
 You are a calendar assistant that can CREATE, VIEW, and DELETE events. Analyze the user's request and respond with a JSON object.
 
 Current time: %s
+Timezone: %s (UTC offset %s)
 
 Calendar events:
 %s
@@ -602,8 +627,8 @@ Respond with a JSON object in this format:
   "events": [
     {
       "summary": "Event Title",
-      "start": "2024-01-15T14:00:00Z",
-      "end": "2024-01-15T15:00:00Z",
+      "start": "2024-01-15T14:00:00%s",
+      "end": "2024-01-15T15:00:00%s",
       "location": "Optional location",
       "description": "Optional description"
     }
@@ -614,7 +639,7 @@ Rules:
 1. For CREATE requests: Set action to "create" and include event details in events array
 2. For VIEW requests: Set action to "view" and include relevant events in events array
 3. For DELETE requests: Set action to "delete" and include events to delete in events array
-4. Always use RFC3339 format for dates (YYYY-MM-DDTHH:MM:SSZ)
+4. Always use RFC3339 format for dates with the user's offset (YYYY-MM-DDTHH:MM:SS%s)
 5. If creating events, avoid conflicts with existing events
 6. Be helpful and conversational in your answer
 
@@ -622,7 +647,7 @@ Examples:
 - "Schedule gym tomorrow at 2pm" → action: "create"
 - "What's my schedule today?" → action: "view"
 - "Delete my gym session" → action: "delete"
-`, currentTime, eventSummary, question)
+`, currentTime, timeZone, offset, eventSummary, question, offset, offset, offset)
 
 	return prompt
 }
@@ -683,6 +708,7 @@ func (q *QueryProcessor) getEventsForSpecificDay(question string, context models
 		if event.Location != "" {
 			answer += fmt.Sprintf(" (%s)", event.Location)
 		}
+		answer += utils.ReminderNote(event)
 		answer += "\n"
 	}
 	
@@ -710,6 +736,7 @@ func (q *QueryProcessor) getAllUpcomingEvents(context models.QueryContext) *mode
 		if event.Location != "" {
 			answer += fmt.Sprintf(" (%s)", event.Location)
 		}
+		answer += utils.ReminderNote(event)
 		answer += "\n"
 	}
 
@@ -756,6 +783,7 @@ func (q *QueryProcessor) findNextEvent(context models.QueryContext) *models.Quer
 	if nextEvent.Location != "" {
 		answer += fmt.Sprintf(" (%s)", nextEvent.Location)
 	}
+	answer += utils.ReminderNote(*nextEvent)
 
 	return &models.QueryResponse{
 		Answer:  answer,
@@ -788,6 +816,7 @@ func (q *QueryProcessor) findEventByKeyword(keyword string, context models.Query
 		if event.Location != "" {
 			answer += fmt.Sprintf(" (%s)", event.Location)
 		}
+		answer += utils.ReminderNote(event)
 		answer += "\n"
 	}
 
@@ -798,6 +827,122 @@ func (q *QueryProcessor) findEventByKeyword(keyword string, context models.Query
 	}
 }
 
+// isRSVPQuery checks if the question asks who has replied to an invitation,
+// e.g. "who hasn't responded to Friday's review?"
+func (q *QueryProcessor) isRSVPQuery(question string) bool {
+	question = strings.ToLower(strings.TrimSpace(question))
+	rsvpKeywords := []string{
+		"rsvp", "responded", "replied", "accepted", "declined", "who's coming",
+		"who is coming", "who's attending", "who is attending",
+	}
+
+	for _, keyword := range rsvpKeywords {
+		if strings.Contains(question, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRSVPs reports how each attendee of the event the question is about
+// has replied. The event is the one with attendees, on the day the question
+// names if any, whose title shares the most words with the question.
+func (q *QueryProcessor) findRSVPs(question string, context models.QueryContext) *models.QueryResponse {
+	allEvents := append(context.TodaysEvents, context.UpcomingEvents...)
+	day, hasDay := rsvpDay(question, context.CurrentTime)
+
+	var event *models.Task
+	best := 0
+	for i, candidate := range allEvents {
+		if len(candidate.Attendees) == 0 {
+			continue
+		}
+		if hasDay {
+			start, err := time.Parse(time.RFC3339, candidate.Start)
+			if err != nil || start.In(context.CurrentTime.Location()).Format("2006-01-02") != day {
+				continue
+			}
+		}
+		score := 0
+		for _, word := range strings.Fields(strings.ToLower(candidate.Summary)) {
+			if len(word) > 2 && strings.Contains(question, word) {
+				score++
+			}
+		}
+		if event == nil || score > best {
+			event = &allEvents[i]
+			best = score
+		}
+	}
+
+	if event == nil {
+		return &models.QueryResponse{
+			Answer:  "I couldn't find an upcoming event with guests that matches your question.",
+			Success: true,
+		}
+	}
+
+	var waiting, accepted, tentative, declined []string
+	for _, attendee := range event.Attendees {
+		switch attendee.ResponseStatus {
+		case models.ResponseAccepted:
+			accepted = append(accepted, attendee.Label())
+		case models.ResponseTentative:
+			tentative = append(tentative, attendee.Label())
+		case models.ResponseDeclined:
+			declined = append(declined, attendee.Label())
+		default:
+			waiting = append(waiting, attendee.Label())
+		}
+	}
+
+	what := fmt.Sprintf("%s (%s)", event.Summary, utils.FormatDateTime(event.Start))
+	answer := fmt.Sprintf("Everyone has responded to %s.", what)
+	if len(waiting) > 0 {
+		verb := "haven't"
+		if len(waiting) == 1 {
+			verb = "hasn't"
+		}
+		answer = fmt.Sprintf("%s %s responded to %s yet.", utils.JoinNames(waiting), verb, what)
+	}
+	for _, reply := range []struct {
+		names []string
+		verb  string
+	}{
+		{accepted, "accepted"},
+		{tentative, "said maybe"},
+		{declined, "declined"},
+	} {
+		if len(reply.names) > 0 {
+			answer += fmt.Sprintf(" %s %s.", utils.JoinNames(reply.names), reply.verb)
+		}
+	}
+
+	return &models.QueryResponse{
+		Answer:  answer,
+		Events:  []models.Task{*event},
+		Success: true,
+	}
+}
+
+// rsvpDay returns the day, YYYY-MM-DD, an RSVP question names: today,
+// tomorrow or the next such weekday, counting today
+func rsvpDay(question string, now time.Time) (string, bool) {
+	if strings.Contains(question, "today") {
+		return now.Format("2006-01-02"), true
+	}
+	if strings.Contains(question, "tomorrow") {
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), true
+	}
+	for offset := 0; offset < 7; offset++ {
+		day := now.AddDate(0, 0, offset)
+		if strings.Contains(question, strings.ToLower(day.Weekday().String())) {
+			return day.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
 // getTodaysSchedule returns today's schedule
 func (q *QueryProcessor) getTodaysSchedule(context models.QueryContext) *models.QueryResponse {
 	if len(context.TodaysEvents) == 0 {
@@ -813,6 +958,7 @@ func (q *QueryProcessor) getTodaysSchedule(context models.QueryContext) *models.
 		if event.Location != "" {
 			answer += fmt.Sprintf(" (%s)", event.Location)
 		}
+		answer += utils.ReminderNote(event)
 		answer += "\n"
 	}
 
@@ -825,6 +971,24 @@ func (q *QueryProcessor) getTodaysSchedule(context models.QueryContext) *models.
 
 // findFreeTime finds available free time slots
 func (q *QueryProcessor) findFreeTime(context models.QueryContext) *models.QueryResponse {
+	// With an availability profile, today's free time is already known
+	if context.Availability != nil {
+		if len(context.FreeToday) == 0 {
+			return &models.QueryResponse{
+				Answer:  "You have no free time left today within your working hours.",
+				Success: true,
+			}
+		}
+		var freeSlots []string
+		for _, slot := range context.FreeToday {
+			freeSlots = append(freeSlots, fmt.Sprintf("%s - %s", slot.Start.Format("15:04"), slot.End.Format("15:04")))
+		}
+		return &models.QueryResponse{
+			Answer:  fmt.Sprintf("You're free today: %s", strings.Join(freeSlots, ", ")),
+			Success: true,
+		}
+	}
+
 	allEvents := append(context.TodaysEvents, context.UpcomingEvents...)
 	
 	if len(allEvents) == 0 {
@@ -1024,12 +1188,17 @@ func (q *QueryProcessor) handleSchedulingQuery(question string, context models.Q
 }
 func (q *QueryProcessor) createSchedulingPrompt(question string, context models.QueryContext) string {
 	currentTime := context.CurrentTime.Format("2006-01-02 15:04:05")
+	timeZone := context.CurrentTime.Location().String()
+	if context.TimeZone != "" {
+		timeZone = context.TimeZone
+	}
+	offset := context.CurrentTime.Format("-07:00")
 	
 	prompt := fmt.Sprintf(`
 You are a calendar assistant. The user wants to schedule an event based on their request: "%s"
 
 Current time: %s
-Current timezone: Asia/Kolkata
+Current timezone: %s
 
 Please extract the event details and respond with a JSON array containing the events to create.
 Each event should have:
@@ -1043,21 +1212,21 @@ Example response:
 [
   {
     "summary": "Team Meeting",
-    "start": "2025-01-15T14:00:00+05:30",
-    "end": "2025-01-15T15:00:00+05:30",
+    "start": "2025-01-15T14:00:00%s",
+    "end": "2025-01-15T15:00:00%s",
     "location": "",
     "description": ""
   }
 ]
 
 Important:
-- Use Asia/Kolkata timezone (+05:30)
+- Use %s timezone (%s)
 - If no specific time is mentioned, use reasonable defaults
 - If duration is not specified, default to 1 hour
 - Only respond with the JSON array, no additional text
 
 User request: "%s"
-`, question, currentTime, question)
+`, question, currentTime, timeZone, offset, offset, timeZone, offset, question)
 
 	return prompt
 }
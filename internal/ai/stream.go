@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StreamingClient is a Client that can deliver a plan as it is generated
+type StreamingClient interface {
+	Client
+	// StreamPlan calls onDelta with each piece of the response as it
+	// arrives and returns the complete response
+	StreamPlan(prompt string, onDelta func(string)) (string, error)
+}
+
+// StreamPlan is GeneratePlan with the response delivered to onDelta as it
+// is generated. Clients that can't stream deliver their whole response at
+// once. Once part of a response has been delivered, a failure is returned
+// rather than trying the next client, since the delivered part can't be
+// taken back.
+func (m *Manager) StreamPlan(prompt string, onDelta func(string)) (string, error) {
+	result, err := m.streamPlan(prompt, onDelta)
+	if m.cassette != nil {
+		m.recordPlan(prompt, result, err)
+	}
+	return result, err
+}
+
+// streamPlan streams the plan of the first AI client that answers
+func (m *Manager) streamPlan(prompt string, onDelta func(string)) (string, error) {
+	if len(m.clients) == 0 {
+		return "", errNoProvider
+	}
+
+	var failures []string
+	for _, client := range m.clients {
+		streamer, ok := client.(StreamingClient)
+		if !ok {
+			result, err := client.GeneratePlan(prompt)
+			if err == nil {
+				onDelta(result)
+				return result, nil
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", client.GetName(), err))
+			continue
+		}
+
+		delivered := false
+		result, err := streamer.StreamPlan(prompt, func(delta string) {
+			delivered = true
+			onDelta(delta)
+		})
+		if err == nil {
+			return result, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", client.GetName(), err))
+		if delivered {
+			break
+		}
+	}
+
+	return "", fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
+}
+
+// readServerSentEvents calls handle with the event name and data of each
+// Server-Sent Event in body until handle reports it is done or body ends
+func readServerSentEvents(body io.Reader, handle func(event, data string) (done bool, err error)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event
+			if len(data) > 0 {
+				done, err := handle(event, strings.Join(data, "\n"))
+				if done || err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %v", err)
+	}
+
+	if len(data) > 0 {
+		_, err := handle(event, strings.Join(data, "\n"))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/cassette"
+)
+
+// toolSystemPrompt is sent with every tool-calling request
+const toolSystemPrompt = "You are a calendar assistant. Always act by calling exactly one of the provided tools; never answer in plain text."
+
+// Tool is a function the model may call. Parameters is a JSON schema object.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall is a tool chosen by the model, with its JSON arguments
+type ToolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ToolClient is implemented by clients whose models can call tools
+type ToolClient interface {
+	CallTools(prompt string, tools []Tool) ([]ToolCall, error)
+}
+
+// SupportsTools returns true if any client can call tools
+func (m *Manager) SupportsTools() bool {
+	for _, client := range m.clients {
+		if _, ok := client.(ToolClient); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CallTools asks the tool-capable clients in order which tools to call,
+// recording the outcome when a cassette is being recorded
+func (m *Manager) CallTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	calls, err := m.callTools(prompt, tools)
+	if m.cassette != nil {
+		m.recordToolCalls(prompt, calls, err)
+	}
+	return calls, err
+}
+
+// callTools returns the tool calls of the first tool-capable client that
+// answers with valid calls. Every client's answer, including one replayed
+// from a cassette, is checked here so callers can rely on at least one call.
+func (m *Manager) callTools(prompt string, tools []Tool) ([]ToolCall, error) {
+	var failures []string
+	for _, client := range m.clients {
+		toolClient, ok := client.(ToolClient)
+		if !ok {
+			continue
+		}
+
+		calls, err := toolClient.CallTools(prompt, tools)
+		if err == nil {
+			err = validateToolCalls(calls, tools)
+		}
+		if err == nil {
+			return calls, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", client.GetName(), err))
+	}
+
+	if len(failures) == 0 {
+		return nil, fmt.Errorf("no configured AI provider supports tool calling")
+	}
+	return nil, fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
+}
+
+// validateToolCalls checks that the model called at least one of the offered tools
+func validateToolCalls(calls []ToolCall, tools []Tool) error {
+	if len(calls) == 0 {
+		return fmt.Errorf("the model did not call a tool")
+	}
+	for _, call := range calls {
+		known := false
+		for _, tool := range tools {
+			if tool.Name == call.Name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("the model called unknown tool %q", call.Name)
+		}
+		if !json.Valid(call.Arguments) {
+			return fmt.Errorf("the model called %s with invalid arguments", call.Name)
+		}
+	}
+	return nil
+}
+
+// recordToolCalls stores a CallTools call in the manager's cassette
+func (m *Manager) recordToolCalls(prompt string, calls []ToolCall, err error) {
+	var result interface{}
+	if err == nil {
+		result = calls
+	}
+	if recordErr := m.cassette.Record(cassette.KindLLM, "CallTools", planRequest{Prompt: prompt}, result, err); recordErr != nil {
+		fmt.Printf("⚠️ Failed to record AI tool calls: %v\n", recordErr)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// attendeesBusy returns when a new event's attendees are busy on the days
+// it spans, as events, and the attendees whose calendars couldn't be checked
+func (s *Server) attendeesBusy(scheduler *planner.EnhancedScheduler, task models.Task) ([]models.Task, []models.Attendee) {
+	if len(task.Attendees) == 0 {
+		return nil, nil
+	}
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return nil, nil
+	}
+
+	location := scheduler.GetLocation()
+	start := parsed.StartTime.In(location)
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+
+	busy, unchecked, err := scheduler.GetQueryService().GetAttendeesBusy(task.Attendees, dayStart, parsed.EndTime.Add(24*time.Hour))
+	if err != nil {
+		fmt.Printf("⚠️ Could not look up free/busy for %s: %v\n", task.Summary, err)
+	}
+	return busy, unchecked
+}
+
+// checkedAttendees returns the labels of the attendees not in unchecked
+func checkedAttendees(attendees []models.Attendee, unchecked []models.Attendee) []string {
+	var labels []string
+	for _, attendee := range attendees {
+		found := false
+		for _, other := range unchecked {
+			if other == attendee {
+				found = true
+				break
+			}
+		}
+		if !found {
+			labels = append(labels, attendee.Label())
+		}
+	}
+	return labels
+}
+
+// noteUncheckedAttendees tells the user whose calendars couldn't be checked,
+// so those people may not be free
+func noteUncheckedAttendees(response map[string]interface{}, unchecked []models.Attendee) {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, attendee := range unchecked {
+		if label := attendee.Label(); !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		return
+	}
+
+	note := fmt.Sprintf("I couldn't check %s's calendar, so they may not be free.", labels[0])
+	if len(labels) > 1 {
+		note = fmt.Sprintf("I couldn't check the calendars of %s, so they may not be free.", utils.JoinNames(labels))
+	}
+	answer := strings.TrimSpace(fmt.Sprint(response["answer"]))
+	if !strings.HasSuffix(answer, ".") {
+		answer += "."
+	}
+	response["answer"] = answer + " " + note
+	response["unchecked_attendees"] = unchecked
+}
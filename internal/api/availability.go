@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// handleGetAvailability handles GET /api/availability
+func (s *Server) handleGetAvailability(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.scheduler.GetAvailability().Profile())
+}
+
+// handlePutAvailability handles PUT /api/availability, replacing the whole
+// profile. Days left out of working_hours are not worked.
+func (s *Server) handlePutAvailability(w http.ResponseWriter, r *http.Request) {
+	var profile models.AvailabilityProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid JSON request")
+		return
+	}
+	if profile.WorkingHours == nil {
+		s.writeError(w, http.StatusBadRequest, "working_hours is required")
+		return
+	}
+
+	if err := s.scheduler.GetAvailability().SetProfile(profile); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fmt.Printf("🗓️ Availability profile updated\n")
+	s.writeJSON(w, http.StatusOK, profile)
+}
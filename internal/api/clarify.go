@@ -0,0 +1,225 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// maxClarificationOptions bounds the choices a clarifying question offers;
+// a delete matching more events than this is handled without asking which
+const maxClarificationOptions = 5
+
+// suggestedSlotCount is how many times are offered for an event without one
+const suggestedSlotCount = 3
+
+// allOptionID is the choice that keeps every candidate
+const allOptionID = "all"
+
+var (
+	// allMatchesPattern marks a delete meant for every event it matches
+	allMatchesPattern = regexp.MustCompile(`\b(all|every|everything|both|entire|whole|events|meetings|appointments|sessions|tasks)\b`)
+
+	// deleteAllPattern marks a delete of every event in its scope
+	deleteAllPattern = regexp.MustCompile(`\ball\b`)
+
+	// partOfDayPattern marks a request that gives a rough time
+	partOfDayPattern = regexp.MustCompile(`\b(morning|afternoon|evening|night|tonight|noon|midnight|breakfast|lunch|dinner|now|asap|o'clock)\b`)
+
+	// ordinalChoices map spoken choices to option IDs
+	ordinalChoices = map[string]string{
+		"first": "1", "second": "2", "third": "3", "fourth": "4", "fifth": "5",
+		"both": allOptionID, "all of them": allOptionID, "everything": allOptionID,
+	}
+)
+
+// writeClarification asks the user to choose how a request should go on.
+// With options, the pending request is kept until the choice arrives.
+func (s *Server) writeClarification(w http.ResponseWriter, pending pendingAction, question string) {
+	clarification := &models.Clarification{
+		Question: question,
+		Request:  pending.request.Question,
+		Options:  pending.options,
+	}
+
+	if len(pending.options) > 0 {
+		token, err := s.clarifications.add(pending)
+		if err != nil {
+			fmt.Printf("❌ Failed to create clarification token: %v\n", err)
+			s.writeError(w, http.StatusInternalServerError, "Failed to ask which option you meant")
+			return
+		}
+		clarification.Token = token
+	}
+
+	fmt.Printf("❓ Asking for clarification: %s\n", question)
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"answer":        question,
+		"success":       true,
+		"action":        "clarify",
+		"clarification": clarification,
+	})
+}
+
+// handleClarificationChoice resumes the request a clarifying question was about
+func (s *Server) handleClarificationChoice(w http.ResponseWriter, req models.QueryRequest) {
+	pending, ok := s.clarifications.peek(req.ClarificationToken)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "clarification token is invalid or has expired")
+		return
+	}
+
+	option, ok := findOption(pending.options, req.Choice)
+	if !ok {
+		var ids []string
+		for _, option := range pending.options {
+			ids = append(ids, option.ID)
+		}
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("choice must be one of: %s", strings.Join(ids, ", ")))
+		return
+	}
+
+	// Each question is answered once
+	if _, ok := s.clarifications.take(req.ClarificationToken); !ok {
+		s.writeError(w, http.StatusBadRequest, "clarification token is invalid or has expired")
+		return
+	}
+
+	fmt.Printf("✅ Clarified with choice %s: %s\n", option.ID, option.Label)
+	request := pending.request
+	request.DryRun = request.DryRun || req.DryRun
+
+	switch pending.action {
+	case "delete":
+		var events []models.Task
+		for _, candidate := range pending.options {
+			if candidate.Event != nil && (option.ID == allOptionID || candidate.ID == option.ID) {
+				events = append(events, *candidate.Event)
+			}
+		}
+		s.writeDeletePlan(w, pending.scheduler, request, events, pending.scope)
+	case "create":
+		s.writeScheduleResult(w, pending.scheduler, request, []models.Task{*option.Event}, pending.events, nil)
+	default:
+		s.writeError(w, http.StatusBadRequest, "clarification token is invalid or has expired")
+	}
+}
+
+// findOption returns the option with the given ID
+func findOption(options []models.ClarificationOption, id string) (models.ClarificationOption, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, option := range options {
+		if option.ID == id {
+			return option, true
+		}
+	}
+	return models.ClarificationOption{}, false
+}
+
+// chosenOption recognizes a reply in a session that just picks one of the
+// options of the open question, like "2", "option 2" or "the second one"
+func chosenOption(conversation *models.Conversation, reply string) (string, bool) {
+	if conversation == nil || conversation.Clarification == nil || conversation.Clarification.Token == "" {
+		return "", false
+	}
+
+	reply = strings.ToLower(strings.Trim(strings.TrimSpace(reply), ".!"))
+	reply = strings.TrimPrefix(reply, "the ")
+	reply = strings.TrimSuffix(reply, " one")
+	reply = strings.TrimPrefix(reply, "option ")
+	reply = strings.TrimPrefix(reply, "#")
+	if id, ok := ordinalChoices[reply]; ok {
+		reply = id
+	}
+
+	_, ok := findOption(conversation.Clarification.Options, reply)
+	return reply, ok
+}
+
+// clarifyDelete asks which events a delete is about when it matched several
+// and the request doesn't say it means all of them. It returns false, without
+// writing a response, when there is nothing to ask.
+func (s *Server) clarifyDelete(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, events []models.Task, scope calendar.RecurrenceScope) bool {
+	events = mergeEvents(nil, events)
+	if len(events) < 2 || len(events) > maxClarificationOptions || allMatchesPattern.MatchString(strings.ToLower(req.Question)) {
+		return false
+	}
+
+	var options []models.ClarificationOption
+	for i := range events {
+		options = append(options, models.ClarificationOption{
+			ID:    strconv.Itoa(i + 1),
+			Label: describeOption(events[i]),
+			Event: &events[i],
+		})
+	}
+	options = append(options, models.ClarificationOption{
+		ID:    allOptionID,
+		Label: fmt.Sprintf("All %d events", len(events)),
+	})
+
+	s.writeClarification(w, pendingAction{
+		action:    "delete",
+		scheduler: scheduler,
+		scope:     scope,
+		request:   req,
+		options:   options,
+	}, fmt.Sprintf("%d events match. Which one should I delete?", len(events)))
+	return true
+}
+
+// clarifySchedule offers free times for a single new event when the request
+// didn't say when, instead of creating it at the AI's guess. It returns
+// false, without writing a response, when there is nothing to ask.
+func (s *Server) clarifySchedule(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, tasks []models.Task, existingTasks []models.Task) bool {
+	if len(tasks) != 1 || tasks[0].IsRecurring() || mentionsTime(scheduler, req.Question) {
+		return false
+	}
+
+	suggestions, err := scheduler.SuggestSlots(tasks[0], suggestedSlotCount)
+	if err != nil {
+		fmt.Printf("⚠️ Could not suggest times, using the AI's: %v\n", err)
+		return false
+	}
+
+	pending := pendingAction{
+		action:    "create",
+		scheduler: scheduler,
+		events:    existingTasks,
+		request:   req,
+	}
+	if len(suggestions) == 0 {
+		s.writeClarification(w, pending, fmt.Sprintf("When should I schedule %s? That day has no free time left.", tasks[0].Summary))
+		return true
+	}
+
+	for i := range suggestions {
+		pending.options = append(pending.options, models.ClarificationOption{
+			ID:    strconv.Itoa(i + 1),
+			Label: describeOption(suggestions[i]),
+			Event: &suggestions[i],
+		})
+	}
+	s.writeClarification(w, pending, fmt.Sprintf("When should I schedule %s?", tasks[0].Summary))
+	return true
+}
+
+// mentionsTime checks whether a request says when something should happen
+func mentionsTime(scheduler *planner.EnhancedScheduler, question string) bool {
+	if scheduler.GetIntentClassifier().ClassifyWithRules(question).Slots.Time != "" {
+		return true
+	}
+	return partOfDayPattern.MatchString(strings.ToLower(question))
+}
+
+// describeOption labels an event for a clarifying question
+func describeOption(event models.Task) string {
+	return fmt.Sprintf("%s, %s - %s", event.Summary, utils.FormatDateTime(event.Start), utils.FormatTime(event.End))
+}
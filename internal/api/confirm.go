@@ -0,0 +1,111 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+)
+
+// confirmationTTL is how long a planned action waits for confirmation
+const confirmationTTL = 10 * time.Minute
+
+// pendingAction is a planned destructive action awaiting confirmation, or a
+// request waiting for the answer to a clarifying question
+type pendingAction struct {
+	action    string
+	scheduler *planner.EnhancedScheduler
+	events    []models.Task
+	scope     calendar.RecurrenceScope
+	expiresAt time.Time
+
+	// For a clarifying question: the request it is about and its answers
+	request models.QueryRequest
+	options []models.ClarificationOption
+}
+
+// confirmationStore keeps planned actions until they are confirmed or expire
+type confirmationStore struct {
+	mu      sync.Mutex
+	pending map[string]pendingAction
+}
+
+// newConfirmationStore creates an empty confirmation store
+func newConfirmationStore() *confirmationStore {
+	return &confirmationStore{
+		pending: make(map[string]pendingAction),
+	}
+}
+
+// add stores a planned action and returns the token that confirms it
+func (cs *confirmationStore) add(action pendingAction) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	now := time.Now()
+	for key, pending := range cs.pending {
+		if now.After(pending.expiresAt) {
+			delete(cs.pending, key)
+		}
+	}
+
+	action.expiresAt = now.Add(confirmationTTL)
+	cs.pending[token] = action
+	return token, nil
+}
+
+// peek returns the action for a token without using it up
+func (cs *confirmationStore) peek(token string) (pendingAction, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	action, exists := cs.pending[token]
+	if !exists || time.Now().After(action.expiresAt) {
+		return pendingAction{}, false
+	}
+	return action, true
+}
+
+// take removes and returns the action for a token. Each token can be used once.
+func (cs *confirmationStore) take(token string) (pendingAction, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	action, exists := cs.pending[token]
+	if !exists {
+		return pendingAction{}, false
+	}
+	delete(cs.pending, token)
+
+	if time.Now().After(action.expiresAt) {
+		return pendingAction{}, false
+	}
+	return action, true
+}
+
+// handleConfirmation executes the action planned by an earlier /api/unified call
+func (s *Server) handleConfirmation(w http.ResponseWriter, req models.QueryRequest) {
+	action, ok := s.confirmations.take(req.ConfirmationToken)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "confirmation token is invalid or has expired")
+		return
+	}
+
+	switch action.action {
+	case "delete":
+		s.executeDelete(w, action.scheduler, action.events, action.scope)
+	default:
+		s.writeError(w, http.StatusBadRequest, "confirmation token is invalid or has expired")
+	}
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// conflictAlternativeCount is how many free times are proposed for a new
+// event that overlaps existing ones
+const conflictAlternativeCount = 3
+
+// scheduleConflict returns the conflict a new, non-recurring event would
+// cause, or nil if it fits. Besides the calendar, the event is checked
+// against existingTasks. Soft conflicts under the conflict policy are only
+// reported. For hard ones, alternatives also avoid the events planned so
+// far; with auto_resolve the best one is chosen.
+func (s *Server) scheduleConflict(scheduler *planner.EnhancedScheduler, req models.QueryRequest, task models.Task, existingTasks []models.Task, planned []models.Task) *models.ScheduleConflict {
+	busy, err := s.eventsAround(scheduler, task)
+	if err != nil {
+		fmt.Printf("⚠️ Could not look up events around %s: %v\n", task.Summary, err)
+	}
+	busy = mergeEvents(busy, existingTasks)
+
+	var hard, soft []planner.EventConflict
+	for _, conflict := range scheduler.GetConflictChecker().Conflicts(task, busy) {
+		if conflict.Severity == calendar.SeverityHard {
+			hard = append(hard, conflict)
+		} else {
+			soft = append(soft, conflict)
+		}
+	}
+	if len(hard) == 0 && len(soft) == 0 {
+		return nil
+	}
+
+	if len(hard) == 0 {
+		conflict := &models.ScheduleConflict{
+			Event:         task,
+			ConflictsWith: conflictingEvents(soft),
+			Severity:      calendar.SeveritySoft,
+			Reason:        describeConflict(task, soft),
+		}
+		fmt.Printf("⚠️ %s\n", conflict.Reason)
+		return conflict
+	}
+
+	conflict := &models.ScheduleConflict{
+		Event:         task,
+		ConflictsWith: conflictingEvents(hard),
+		Severity:      calendar.SeverityHard,
+		Reason:        describeConflict(task, hard),
+	}
+
+	alternatives, err := scheduler.ProposeAlternatives(task, mergeEvents(busy, planned), conflictAlternativeCount)
+	if err != nil {
+		fmt.Printf("⚠️ Could not propose other times for %s: %v\n", task.Summary, err)
+	}
+	conflict.Alternatives = alternatives
+	if req.AutoResolve && len(alternatives) > 0 {
+		conflict.ResolvedTo = &alternatives[0]
+	}
+
+	fmt.Printf("⚠️ %s\n", conflict.Reason)
+	return conflict
+}
+
+// recurringScheduleConflict is scheduleConflict for a recurring task. Every
+// occurrence is checked against the calendar, the attendees' busy times and
+// existingTasks. Alternatives move the whole series to a time that is free
+// for every occurrence and avoids the events planned so far. The attendees
+// whose calendars couldn't be checked are returned too.
+func (s *Server) recurringScheduleConflict(scheduler *planner.EnhancedScheduler, req models.QueryRequest, task models.Task, existingTasks []models.Task, planned []models.Task) (*models.ScheduleConflict, []models.Attendee, error) {
+	occurrences, err := scheduler.ExpandOccurrences(task)
+	if err != nil || len(occurrences) == 0 {
+		return nil, nil, err
+	}
+
+	busy, unchecked, err := s.occurrencesBusy(scheduler, task, occurrences)
+	if err != nil {
+		fmt.Printf("⚠️ Could not look up events around %s: %v\n", task.Summary, err)
+	}
+	busy = mergeEvents(busy, existingTasks)
+
+	hard, soft := occurrenceConflicts(scheduler, occurrences, busy)
+	if len(hard) == 0 && len(soft) == 0 {
+		return nil, unchecked, nil
+	}
+
+	if len(hard) == 0 {
+		conflict := &models.ScheduleConflict{
+			Event:         task,
+			ConflictsWith: blockingEvents(soft),
+			Severity:      calendar.SeveritySoft,
+			Reason:        describeOccurrenceConflicts(soft),
+		}
+		fmt.Printf("⚠️ %s\n", conflict.Reason)
+		return conflict, unchecked, nil
+	}
+
+	conflict := &models.ScheduleConflict{
+		Event:         task,
+		ConflictsWith: blockingEvents(hard),
+		Severity:      calendar.SeverityHard,
+		Reason:        describeOccurrenceConflicts(hard),
+	}
+
+	// Times that suit the first blocked occurrence are tried for the whole series
+	busy = mergeEvents(busy, planned)
+	candidates, err := scheduler.ProposeAlternatives(hard[0].occurrence, busy, 2*conflictAlternativeCount)
+	if err != nil {
+		fmt.Printf("⚠️ Could not propose other times for %s: %v\n", task.Summary, err)
+	}
+	for _, candidate := range candidates {
+		moved, err := moveSeries(scheduler, task, hard[0].occurrence, candidate)
+		if err != nil {
+			continue
+		}
+		occurrences, err := scheduler.ExpandOccurrences(moved)
+		if err != nil {
+			continue
+		}
+		if blocked, _ := occurrenceConflicts(scheduler, occurrences, busy); len(blocked) == 0 {
+			conflict.Alternatives = append(conflict.Alternatives, moved)
+		}
+		if len(conflict.Alternatives) == conflictAlternativeCount {
+			break
+		}
+	}
+	if req.AutoResolve && len(conflict.Alternatives) > 0 {
+		conflict.ResolvedTo = &conflict.Alternatives[0]
+	}
+
+	fmt.Printf("⚠️ %s\n", conflict.Reason)
+	return conflict, unchecked, nil
+}
+
+// occurrenceConflict is an occurrence of a new recurring event with the
+// existing events it conflicts with
+type occurrenceConflict struct {
+	occurrence models.Task
+	conflicts  []planner.EventConflict
+}
+
+// occurrenceConflicts returns the occurrences with hard conflicts, and those
+// with only soft ones
+func occurrenceConflicts(scheduler *planner.EnhancedScheduler, occurrences []models.Task, busy []models.Task) ([]occurrenceConflict, []occurrenceConflict) {
+	var hard, soft []occurrenceConflict
+	for _, occurrence := range occurrences {
+		var hardConflicts, softConflicts []planner.EventConflict
+		for _, conflict := range scheduler.GetConflictChecker().Conflicts(occurrence, busy) {
+			if conflict.Severity == calendar.SeverityHard {
+				hardConflicts = append(hardConflicts, conflict)
+			} else {
+				softConflicts = append(softConflicts, conflict)
+			}
+		}
+		if len(hardConflicts) > 0 {
+			hard = append(hard, occurrenceConflict{occurrence: occurrence, conflicts: hardConflicts})
+		} else if len(softConflicts) > 0 {
+			soft = append(soft, occurrenceConflict{occurrence: occurrence, conflicts: softConflicts})
+		}
+	}
+	return hard, soft
+}
+
+// occurrencesBusy returns the events in any configured calendar, with the
+// attendees' busy times, from the day of the first occurrence to the day
+// after the last. The attendees whose calendars couldn't be checked are
+// returned too.
+func (s *Server) occurrencesBusy(scheduler *planner.EnhancedScheduler, task models.Task, occurrences []models.Task) ([]models.Task, []models.Attendee, error) {
+	first, err := occurrences[0].ParseTime()
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err := occurrences[len(occurrences)-1].ParseTime()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	location := scheduler.GetLocation()
+	start := first.StartTime.In(location)
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+	end := last.EndTime.Add(24 * time.Hour)
+
+	busy, err := scheduler.GetQueryService().GetEventsByDateRange(dayStart, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(task.Attendees) == 0 {
+		return busy, nil, nil
+	}
+
+	attendeeBusy, unchecked, err := scheduler.GetQueryService().GetAttendeesBusy(task.Attendees, dayStart, end)
+	if err != nil {
+		fmt.Printf("⚠️ Could not look up free/busy for %s: %v\n", task.Summary, err)
+	}
+	return mergeEvents(busy, attendeeBusy), unchecked, nil
+}
+
+// moveSeries moves a recurring task by as much as moving occurrence to
+// alternative takes
+func moveSeries(scheduler *planner.EnhancedScheduler, task, occurrence, alternative models.Task) (models.Task, error) {
+	from, err := occurrence.ParseTime()
+	if err != nil {
+		return task, err
+	}
+	to, err := alternative.ParseTime()
+	if err != nil {
+		return task, err
+	}
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return task, err
+	}
+
+	shift := to.StartTime.Sub(from.StartTime)
+	location := scheduler.GetLocation()
+	task.Start = parsed.StartTime.Add(shift).In(location).Format(time.RFC3339)
+	task.End = parsed.EndTime.Add(shift).In(location).Format(time.RFC3339)
+	return task, nil
+}
+
+// blockingEvents returns the existing events that occurrences conflict with,
+// each once
+func blockingEvents(conflicts []occurrenceConflict) []models.Task {
+	var events []models.Task
+	for _, conflict := range conflicts {
+		events = mergeEvents(events, conflictingEvents(conflict.conflicts))
+	}
+	return events
+}
+
+// describeOccurrenceConflicts explains how the first conflicting occurrence
+// of a new recurring event conflicts, and how many more do
+func describeOccurrenceConflicts(conflicts []occurrenceConflict) string {
+	reason := describeConflict(conflicts[0].occurrence, conflicts[0].conflicts)
+	if len(conflicts) > 1 {
+		reason += fmt.Sprintf(", and %d more occurrence(s) conflict", len(conflicts)-1)
+	}
+	return reason
+}
+
+// conflictingEvents returns the existing events of conflicts
+func conflictingEvents(conflicts []planner.EventConflict) []models.Task {
+	var events []models.Task
+	for _, conflict := range conflicts {
+		events = append(events, conflict.Event)
+	}
+	return events
+}
+
+// describeConflict explains how a new event conflicts with existing events,
+// e.g. "Review at ... overlaps Standup (9:00 AM - 9:15 AM)"
+func describeConflict(task models.Task, conflicts []planner.EventConflict) string {
+	var parts []string
+	for _, conflict := range conflicts {
+		parts = append(parts, fmt.Sprintf("%s %s (%s - %s)", conflict.Reason, conflict.Event.Summary, utils.FormatTime(conflict.Event.Start), utils.FormatTime(conflict.Event.End)))
+	}
+	return fmt.Sprintf("%s at %s %s", task.Summary, utils.FormatDateTime(task.Start), strings.Join(parts, ", "))
+}
+
+// describeConflicts tells the user what happened to each conflicting event
+func describeConflicts(conflicts []models.ScheduleConflict) string {
+	var sentences []string
+	for _, conflict := range conflicts {
+		switch {
+		case conflict.Severity == calendar.SeveritySoft:
+			sentences = append(sentences, conflict.Reason+", but your conflict policy allows it, so I scheduled it anyway.")
+		case conflict.ResolvedTo != nil:
+			sentences = append(sentences, fmt.Sprintf("%s, so I moved it to %s - %s.",
+				conflict.Reason, utils.FormatDateTime(conflict.ResolvedTo.Start), utils.FormatTime(conflict.ResolvedTo.End)))
+		case len(conflict.Alternatives) > 0:
+			sentences = append(sentences, fmt.Sprintf("%s, so I didn't schedule it. The closest free time is %s - %s.",
+				conflict.Reason, utils.FormatDateTime(conflict.Alternatives[0].Start), utils.FormatTime(conflict.Alternatives[0].End)))
+		default:
+			sentences = append(sentences, fmt.Sprintf("%s, so I didn't schedule it, and that day has no free time left.", conflict.Reason))
+		}
+	}
+	return strings.Join(sentences, " ")
+}
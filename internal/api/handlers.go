@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
 	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
 )
 
@@ -58,6 +61,50 @@ func (s *Server) handleUnifiedQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Progress events and the final answer are sent as Server-Sent Events
+	if wantsEventStream(r, req) {
+		stream, ok := newEventStream(w)
+		if !ok {
+			s.writeError(w, http.StatusInternalServerError, "Streaming is not supported")
+			return
+		}
+		w = stream
+	}
+
+	// A session remembers the request and its answer for follow-ups
+	if req.SessionID != "" {
+		if err := validateSessionID(req.SessionID); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		question := req.Question
+		if question == "" && req.ConfirmationToken != "" {
+			question = "Yes, go ahead."
+		} else if question == "" && req.ClarificationToken != "" {
+			question = fmt.Sprintf("Option %s.", req.Choice)
+		}
+		recorder := &sessionRecorder{ResponseWriter: w}
+		w = recorder
+		defer func() {
+			s.sessions.record(req.SessionID, question, recorder.answer())
+		}()
+	}
+
+	// A confirmation executes the action planned by an earlier call
+	if req.ConfirmationToken != "" {
+		fmt.Printf("🔑 Processing confirmation\n")
+		s.handleConfirmation(w, req)
+		return
+	}
+
+	// A choice answers a clarifying question and resumes the request it was about
+	if req.ClarificationToken != "" {
+		fmt.Printf("🔑 Processing clarification choice %q\n", req.Choice)
+		s.handleClarificationChoice(w, req)
+		return
+	}
+
 	if req.Question == "" {
 		s.writeError(w, http.StatusBadRequest, "question is required")
 		return
@@ -65,76 +112,63 @@ func (s *Server) handleUnifiedQuery(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("🔍 Processing unified query: %s\n", req.Question)
 
-	// More precise intent detection
-	if isExplicitSchedulingRequest(req.Question) {
-		fmt.Printf("🎯 Detected explicit scheduling intent - routing to schedule logic\n")
-		s.handleSchedulingFromQuery(w, req.Question)
+	scheduler, err := s.schedulerFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if isExplicitDeleteRequest(req.Question) {
-		fmt.Printf("🎯 Detected explicit delete intent - routing to delete logic\n")
-		s.handleDeleteFromQuery(w, req.Question)
+	// Earlier turns of the session go into the prompts, and a reply to a
+	// clarification is handled together with the request it clarifies
+	conversation := s.sessions.conversation(req.SessionID)
+	if choice, ok := chosenOption(conversation, req.Question); ok {
+		req.ClarificationToken = conversation.Clarification.Token
+		req.Choice = choice
+		s.handleClarificationChoice(w, req)
 		return
 	}
+	scheduler = scheduler.WithConversation(conversation)
+	req.Question = resolveClarification(scheduler, conversation, req.Question)
 
-	// Default to query/view logic
-	fmt.Printf("🎯 Detected view intent - routing to query logic\n")
-	s.handleViewFromQuery(w, req.Question)
-}
+	// Let the model pick the action by calling a tool; keyword routing is
+	// the fallback for providers without tool calling
+	if scheduler.GetAIManager().SupportsTools() && s.handleWithTools(w, scheduler, req) {
+		return
+	}
 
+	intent := scheduler.GetIntentClassifier().Classify(req.Question)
+	fmt.Printf("🎯 Detected %s intent (confidence %.2f, %s)\n", intent.Intent, intent.Confidence, intent.Source)
+	s.progress(w, progressIntent, intent)
 
-// More precise scheduling detection
-func isExplicitSchedulingRequest(question string) bool {
-	question = strings.ToLower(strings.TrimSpace(question))
-	
-	// First check for VIEW keywords - if present, it's NOT a scheduling request
-	viewKeywords := []string{
-		"what's", "what is", "show me", "tell me", "display", "view", "see",
-		"check", "look at", "find", "get", "list", "when is", "when are",
-		"my schedule", "schedule for", "events for",
-	}
-	
-	for _, keyword := range viewKeywords {
-		if strings.Contains(question, keyword) {
-			return false
-		}
-	}
-	
-	// Explicit scheduling words - UPDATED to include "create"
-	explicitScheduleKeywords := []string{
-		"schedule a", "schedule an", "schedule", 
-		"book a", "book an", "book",
-		"add a", "add an", "add",
-		"create a", "create an", "create", // Added these
-		"set up a", "set up an", "set up",
-		"plan a", "plan an", "plan",
-		"arrange a", "arrange an", "arrange",
-		"organize a", "organize an", "organize",
-	}
-	
-	for _, keyword := range explicitScheduleKeywords {
-		if strings.Contains(question, keyword) {
-			return true
-		}
+	switch intent.Intent {
+	case planner.IntentUndo:
+		s.handleUndoFromQuery(w, scheduler, req.Question)
+	case planner.IntentUpdate:
+		s.handleUpdateFromQuery(w, scheduler, req)
+	case planner.IntentCreate:
+		s.handleSchedulingFromQuery(w, scheduler, req)
+	case planner.IntentDelete:
+		s.handleDeleteFromQuery(w, scheduler, req)
+	default:
+		s.handleViewFromQuery(w, scheduler, req)
 	}
-	
-	return false
 }
 
 
-func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, question string) {
+func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest) {
+	question := req.Question
 	fmt.Printf("📅 Processing scheduling request: %s\n", question)
 
-	// Get existing events
-	existingTasks, err := s.scheduler.GetCalendarClient().GetTodaysEvents()
+	// Get existing events across all configured calendars
+	existingTasks, err := scheduler.GetQueryService().GetTodaysSchedule()
 	if err != nil {
 		existingTasks = []models.Task{}
 	}
+	s.progress(w, progressCalendar, map[string]int{"events": len(existingTasks)})
 
 	// Generate plan with AI (same logic as handleSchedule)
-	prompt := s.scheduler.GetPromptGenerator().CreateRestrictivePrompt(existingTasks, question)
-	planJSON, err := s.scheduler.GetAIManager().GeneratePlan(prompt)
+	prompt := scheduler.GetPromptGenerator().CreateRestrictivePrompt(existingTasks, question)
+	planJSON, err := s.generatePlan(w, scheduler, prompt)
 	if err != nil {
 		fmt.Printf("❌ AI planning failed: %v\n", err)
 		s.writeError(w, http.StatusInternalServerError, "AI service unavailable. Please try again later.")
@@ -150,7 +184,20 @@ func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, question strin
 		s.writeError(w, http.StatusInternalServerError, "Failed to understand your request. Please be more specific.")
 		return
 	}
+	tasks = scheduler.AddAttendees(tasks, req.Question)
+	s.progress(w, progressPlan, map[string]interface{}{"events": tasks})
+
+	if s.scheduleInFreeSlot(w, scheduler, req, tasks, existingTasks) || s.clarifySchedule(w, scheduler, req, tasks, existingTasks) {
+		return
+	}
+	s.writeScheduleResult(w, scheduler, req, tasks, existingTasks, nil)
+}
 
+// writeScheduleResult creates the planned tasks that don't conflict with the
+// calendar or existingTasks, or previews them for a dry run. Conflicting
+// tasks are reported with alternative times, and with auto_resolve are
+// created at the best one. Details are added to a successful response.
+func (s *Server) writeScheduleResult(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, tasks []models.Task, existingTasks []models.Task, details map[string]interface{}) {
 	// Validate that the AI only created what was requested - INCREASED LIMIT
 	if len(tasks) > 5 { // Increased from 3 to 5
 		fmt.Printf("⚠️ AI generated too many events (%d), rejecting\n", len(tasks))
@@ -158,23 +205,103 @@ func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, question strin
 		return
 	}
 
-	// Filter out conflicting tasks
+	// Filter out conflicting tasks. Recurring tasks are checked occurrence by occurrence.
 	var validTasks []models.Task
+	var conflicts []string
+	var scheduleConflicts []models.ScheduleConflict
+	var unchecked []models.Attendee
 	for _, task := range tasks {
-		if !s.scheduler.GetConflictChecker().HasTimeConflict(task, existingTasks) {
+		var conflict *models.ScheduleConflict
+		var missing []models.Attendee
+		if task.IsRecurring() {
+			var err error
+			conflict, missing, err = s.recurringScheduleConflict(scheduler, req, task, existingTasks, validTasks)
+			if err != nil {
+				fmt.Printf("⚠️ Skipping recurring task %s: %v\n", task.Summary, err)
+				conflicts = append(conflicts, fmt.Sprintf("%s has an invalid recurrence (%v)", task.Summary, err))
+				continue
+			}
+		} else {
+			// Attendees' busy times count as conflicts too
+			var attendeeBusy []models.Task
+			attendeeBusy, missing = s.attendeesBusy(scheduler, task)
+			conflict = s.scheduleConflict(scheduler, req, task, mergeEvents(existingTasks, attendeeBusy), validTasks)
+		}
+		unchecked = append(unchecked, missing...)
+
+		if conflict != nil {
+			scheduleConflicts = append(scheduleConflicts, *conflict)
+			if conflict.Severity == calendar.SeveritySoft {
+				validTasks = append(validTasks, task)
+			} else if conflict.ResolvedTo != nil {
+				validTasks = append(validTasks, *conflict.ResolvedTo)
+			}
+		} else {
 			validTasks = append(validTasks, task)
 		}
 	}
+	if len(scheduleConflicts) > 0 {
+		if details == nil {
+			details = make(map[string]interface{})
+		}
+		details["conflicts"] = scheduleConflicts
+	}
 
 	if len(validTasks) == 0 {
-		s.writeError(w, http.StatusBadRequest, "No valid events could be created. Please check for time conflicts.")
+		if len(scheduleConflicts) > 0 {
+			response := map[string]interface{}{
+				"answer":    describeConflicts(scheduleConflicts),
+				"success":   false,
+				"action":    "create",
+				"conflicts": scheduleConflicts,
+			}
+			noteUncheckedAttendees(response, unchecked)
+			s.writeJSON(w, http.StatusOK, response)
+			return
+		}
+		message := "No valid events could be created. Please check for time conflicts."
+		if len(conflicts) > 0 {
+			message = fmt.Sprintf("No valid events could be created: %s.", strings.Join(conflicts, "; "))
+		}
+		s.writeError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	if req.DryRun {
+		var eventNames []string
+		for _, task := range validTasks {
+			eventNames = append(eventNames, task.Summary)
+		}
+		answer := fmt.Sprintf("I would schedule %d event(s): %s", len(validTasks), strings.Join(eventNames, ", "))
+		if len(scheduleConflicts) > 0 {
+			answer += ". " + describeConflicts(scheduleConflicts)
+		}
+		response := map[string]interface{}{
+			"answer":  answer,
+			"events":  validTasks,
+			"success": true,
+			"action":  "create",
+			"dry_run": true,
+		}
+		for key, value := range details {
+			response[key] = value
+		}
+		noteUncheckedAttendees(response, unchecked)
+		s.writeJSON(w, http.StatusOK, response)
 		return
 	}
 
 	fmt.Printf("📋 Creating %d valid tasks\n", len(validTasks))
 
 	// Create events
-	eventsAdded, err := s.scheduler.GetCalendarClient().CreateMultipleEvents(validTasks)
+	eventsAdded, err := scheduler.GetCalendarClient().CreateEventsWithProgress(validTasks, func(task models.Task) {
+		s.progress(w, progressEventCreated, task)
+	})
+	// A failed insert is only logged, so nothing added is the failure;
+	// details such as a free-slot answer would otherwise claim success
+	if err == nil && eventsAdded == 0 {
+		err = fmt.Errorf("none of the %d event(s) could be added", len(validTasks))
+	}
 	if err != nil {
 		fmt.Printf("❌ Failed to create events: %v\n", err)
 		s.writeError(w, http.StatusInternalServerError, "Failed to create events")
@@ -186,16 +313,28 @@ func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, question strin
 	// Create a summary of what was created
 	var eventNames []string
 	for _, task := range validTasks {
-		eventNames = append(eventNames, task.Summary)
+		if task.IsRecurring() {
+			eventNames = append(eventNames, task.Summary+" (recurring)")
+		} else {
+			eventNames = append(eventNames, task.Summary)
+		}
 	}
 
+	answer := fmt.Sprintf("I've successfully scheduled %d event(s): %s", eventsAdded, strings.Join(eventNames, ", "))
+	if len(scheduleConflicts) > 0 {
+		answer += ". " + describeConflicts(scheduleConflicts)
+	}
 	response := map[string]interface{}{
-		"answer":       fmt.Sprintf("I've successfully scheduled %d event(s): %s", eventsAdded, strings.Join(eventNames, ", ")),
+		"answer":       answer,
 		"events":       validTasks,
 		"success":      true,
 		"action":       "create", // Make sure this is "create"
 		"events_added": eventsAdded,
 	}
+	for key, value := range details {
+		response[key] = value
+	}
+	noteUncheckedAttendees(response, unchecked)
 
 	s.writeJSON(w, http.StatusOK, response)
 }
@@ -203,21 +342,13 @@ func (s *Server) handleSchedulingFromQuery(w http.ResponseWriter, question strin
 
 
 // handleDeleteFromQuery handles delete requests from natural language
-func (s *Server) handleDeleteFromQuery(w http.ResponseWriter, question string) {
+func (s *Server) handleDeleteFromQuery(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest) {
+	question := req.Question
 	fmt.Printf("🗑️ Processing delete request: %s\n", question)
 
-	// Get all events to search through
-	todaysEvents, err := s.scheduler.GetCalendarClient().GetTodaysEvents()
-	if err != nil {
-		todaysEvents = []models.Task{}
-	}
-
-	upcomingEvents, err := s.scheduler.GetQueryService().GetUpcomingEvents(7)
-	if err != nil {
-		upcomingEvents = []models.Task{}
-	}
-
-	allEvents := append(todaysEvents, upcomingEvents...)
+	// Get all events to search through, each once
+	allEvents := candidateEvents(scheduler)
+	s.progress(w, progressCalendar, map[string]int{"events": len(allEvents)})
 	
 	if len(allEvents) == 0 {
 		response := map[string]interface{}{
@@ -229,49 +360,119 @@ func (s *Server) handleDeleteFromQuery(w http.ResponseWriter, question string) {
 		return
 	}
 
+	scope, err := recurrenceScopeFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Find events to delete based on user's specific request
-	eventsToDelete := s.findEventsToDelete(question, allEvents)
-	
+	eventsToDelete := s.findEventsToDelete(question, allEvents, scheduler.GetCalendarClient().Now())
+	if scope != calendar.ScopeInstance {
+		eventsToDelete = firstOccurrences(eventsToDelete)
+	}
+
+	if s.clarifyDelete(w, scheduler, req, eventsToDelete, scope) {
+		return
+	}
+	s.writeDeletePlan(w, scheduler, req, eventsToDelete, scope)
+}
+
+// writeDeletePlan previews the deletion of events and returns the
+// confirmation token that executes it
+func (s *Server) writeDeletePlan(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, eventsToDelete []models.Task, scope calendar.RecurrenceScope) {
 	if len(eventsToDelete) == 0 {
+		answer := "No matching events found to delete. Please be more specific."
 		response := map[string]interface{}{
-			"answer":  "No matching events found to delete. Please be more specific.",
-			"success": false,
-			"action":  "delete",
+			"answer":        answer,
+			"success":       false,
+			"action":        "delete",
+			"clarification": &models.Clarification{Question: answer, Request: req.Question},
 		}
 		s.writeJSON(w, http.StatusOK, response)
 		return
 	}
 
 	// Confirm what will be deleted (don't delete more than 5 events without explicit "all")
-	if len(eventsToDelete) > 5 && !strings.Contains(strings.ToLower(question), "all") {
+	if len(eventsToDelete) > 5 && !deleteAllPattern.MatchString(strings.ToLower(req.Question)) {
 		var eventNames []string
 		for _, event := range eventsToDelete {
 			eventNames = append(eventNames, event.Summary)
 		}
 		
+		answer := fmt.Sprintf("Found %d events to delete: %s. This seems like a lot. Please be more specific or use 'delete all' if you're sure.", len(eventsToDelete), strings.Join(eventNames, ", "))
 		response := map[string]interface{}{
-			"answer":  fmt.Sprintf("Found %d events to delete: %s. This seems like a lot. Please be more specific or use 'delete all' if you're sure.", len(eventsToDelete), strings.Join(eventNames, ", ")),
-			"events":  eventsToDelete,
-			"success": false,
-			"action":  "delete",
+			"answer":        answer,
+			"events":        eventsToDelete,
+			"success":       false,
+			"action":        "delete",
+			"clarification": &models.Clarification{Question: answer, Request: req.Question},
 		}
 		s.writeJSON(w, http.StatusOK, response)
 		return
 	}
 
+	var eventNames []string
+	for _, event := range eventsToDelete {
+		eventNames = append(eventNames, describeDeletedEvent(event, scope))
+	}
+	preview := fmt.Sprintf("%d event(s) would be deleted: %s.", len(eventsToDelete), strings.Join(eventNames, ", "))
+
+	if req.DryRun {
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"answer":  preview,
+			"events":  eventsToDelete,
+			"success": true,
+			"action":  "delete",
+			"dry_run": true,
+		})
+		return
+	}
+
+	// Deleting is two-phase: return the plan and a token that executes it
+	token, err := s.confirmations.add(pendingAction{
+		action:    "delete",
+		scheduler: scheduler,
+		events:    eventsToDelete,
+		scope:     scope,
+	})
+	if err != nil {
+		fmt.Printf("❌ Failed to create confirmation token: %v\n", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to plan the deletion")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"answer":                fmt.Sprintf("%s Send the confirmation_token to confirm.", preview),
+		"events":                eventsToDelete,
+		"success":               true,
+		"action":                "delete",
+		"requires_confirmation": true,
+		"confirmation_token":    token,
+		"expires_in":            int(confirmationTTL.Seconds()),
+	})
+}
+
+// executeDelete deletes planned events and writes the result
+func (s *Server) executeDelete(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, eventsToDelete []models.Task, scope calendar.RecurrenceScope) {
 	// Perform the deletion
-	calendarClient := s.scheduler.GetCalendarClient()
+	calendarClient := scheduler.GetCalendarClient()
 	deletedEvents := []models.Task{}
 	failedDeletes := []models.Task{}
 
 	fmt.Printf("🗑️ Attempting to delete %d events\n", len(eventsToDelete))
 
 	for _, event := range eventsToDelete {
+		// Delete from the calendar the event was found in
+		eventClient := calendarClient.WithCalendar(event.CalendarID)
+
 		var err error
-		if event.EventID != "" {
-			err = calendarClient.DeleteEvent(event.EventID)
+		if event.RecurringEventID != "" {
+			err = eventClient.DeleteRecurringEvent(event, scope)
+		} else if event.EventID != "" {
+			err = eventClient.DeleteEvent(event.EventID)
 		} else {
-			err = calendarClient.DeleteEventBySummaryAndTime(event.Summary, event.Start, event.End)
+			err = eventClient.DeleteEventBySummaryAndTime(event.Summary, event.Start, event.End)
 		}
 		
 		if err == nil {
@@ -290,13 +491,16 @@ func (s *Server) handleDeleteFromQuery(w http.ResponseWriter, question string) {
 	if len(deletedEvents) > 0 {
 		var eventNames []string
 		for _, event := range deletedEvents {
-			eventNames = append(eventNames, event.Summary)
+			eventNames = append(eventNames, describeDeletedEvent(event, scope))
 		}
 		answer = fmt.Sprintf("Successfully deleted %d event(s): %s", len(deletedEvents), strings.Join(eventNames, ", "))
 		
 		if len(failedDeletes) > 0 {
 			answer += fmt.Sprintf(". Failed to delete %d event(s).", len(failedDeletes))
 		}
+		if calendarClient.GetJournal() != nil {
+			answer += undoHint(len(deletedEvents))
+		}
 	} else {
 		answer = "Failed to delete any events. Please try again."
 	}
@@ -308,20 +512,26 @@ func (s *Server) handleDeleteFromQuery(w http.ResponseWriter, question string) {
 		"action":         "delete",
 		"events_deleted": len(deletedEvents),
 	}
+	for _, event := range deletedEvents {
+		if event.RecurringEventID != "" {
+			response["scope"] = scope
+			break
+		}
+	}
 
 	s.writeJSON(w, http.StatusOK, response)
 }
 
 
-func (s *Server) findEventsToDelete(question string, allEvents []models.Task) []models.Task {
+func (s *Server) findEventsToDelete(question string, allEvents []models.Task, now time.Time) []models.Task {
 	question = strings.ToLower(strings.TrimSpace(question))
 	var eventsToDelete []models.Task
 	
 	// Check for "all" keyword with specific scope
-	if strings.Contains(question, "all") {
+	if deleteAllPattern.MatchString(question) {
 		if strings.Contains(question, "today") {
 			// Delete all today's events
-			today := time.Now().Format("2006-01-02")
+			today := now.Format("2006-01-02")
 			for _, event := range allEvents {
 				eventTime, err := time.Parse(time.RFC3339, event.Start)
 				if err == nil && eventTime.Format("2006-01-02") == today {
@@ -330,13 +540,16 @@ func (s *Server) findEventsToDelete(question string, allEvents []models.Task) []
 			}
 		} else if strings.Contains(question, "tomorrow") {
 			// Delete all tomorrow's events
-			tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+			tomorrow := now.AddDate(0, 0, 1).Format("2006-01-02")
 			for _, event := range allEvents {
 				eventTime, err := time.Parse(time.RFC3339, event.Start)
 				if err == nil && eventTime.Format("2006-01-02") == tomorrow {
 					eventsToDelete = append(eventsToDelete, event)
 				}
 			}
+		} else if keywordEvents := s.findEventsByKeywords(question, allEvents); len(keywordEvents) > 0 {
+			// "all" of one kind of event, e.g. "cancel all future standups"
+			eventsToDelete = keywordEvents
 		} else {
 			// Delete all events (be careful with this)
 			eventsToDelete = allEvents
@@ -344,40 +557,61 @@ func (s *Server) findEventsToDelete(question string, allEvents []models.Task) []
 		return eventsToDelete
 	}
 
-	// Check for specific date mentions
-	if dateEvents := s.findEventsByDate(question, allEvents); len(dateEvents) > 0 {
+	// Check for specific date mentions, narrowed to the named event if any
+	if dateEvents := s.findEventsByDate(question, allEvents, now); len(dateEvents) > 0 {
+		if keywordEvents := s.findEventsByKeywords(question, dateEvents); len(keywordEvents) > 0 {
+			return keywordEvents
+		}
 		return dateEvents
 	}
 
-	// Check for specific event keywords
+	return s.findEventsByKeywords(question, allEvents)
+}
+
+// findEventsByKeywords returns the events whose summary matches the most
+// keywords of the question, so "late standups" prefers "Late standup" over "Standup"
+func (s *Server) findEventsByKeywords(question string, allEvents []models.Task) []models.Task {
+	var eventsToDelete []models.Task
+	best := 0
+
 	keywords := s.extractKeywords(question)
-	for _, keyword := range keywords {
-		for _, event := range allEvents {
-			if strings.Contains(strings.ToLower(event.Summary), keyword) {
-				// Avoid duplicates
-				found := false
-				for _, existing := range eventsToDelete {
-					if existing.EventID == event.EventID || 
-					   (existing.Summary == event.Summary && existing.Start == event.Start) {
-						found = true
-						break
-					}
-				}
-				if !found {
-					eventsToDelete = append(eventsToDelete, event)
-				}
+	for _, event := range allEvents {
+		summary := strings.ToLower(event.Summary)
+		matches := 0
+		for _, keyword := range keywords {
+			if strings.Contains(summary, keyword) || strings.Contains(summary, strings.TrimSuffix(keyword, "s")) {
+				matches++
+			}
+		}
+		if matches == 0 || matches < best {
+			continue
+		}
+		if matches > best {
+			best = matches
+			eventsToDelete = nil
+		}
+
+		// Avoid duplicates
+		found := false
+		for _, existing := range eventsToDelete {
+			if existing.EventID == event.EventID ||
+				(existing.Summary == event.Summary && existing.Start == event.Start) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			eventsToDelete = append(eventsToDelete, event)
+		}
 	}
 
 	return eventsToDelete
 }
 
 
-func (s *Server) findEventsByDate(question string, allEvents []models.Task) []models.Task {
+func (s *Server) findEventsByDate(question string, allEvents []models.Task, now time.Time) []models.Task {
 	var eventsForDate []models.Task
 	var targetDate time.Time
-	now := time.Now()
 	
 	if strings.Contains(question, "today") {
 		targetDate = now
@@ -385,6 +619,9 @@ func (s *Server) findEventsByDate(question string, allEvents []models.Task) []mo
 		targetDate = now.AddDate(0, 0, 1)
 	} else if strings.Contains(question, "yesterday") {
 		targetDate = now.AddDate(0, 0, -1)
+	} else if weekday, ok := mentionedWeekday(question); ok {
+		// The next such day, counting today
+		targetDate = now.AddDate(0, 0, (int(weekday)-int(now.Weekday())+7)%7)
 	} else {
 		// Try to parse specific dates (you can extend this)
 		return eventsForDate
@@ -401,13 +638,64 @@ func (s *Server) findEventsByDate(question string, allEvents []models.Task) []mo
 	return eventsForDate
 }
 
+// mentionedWeekday returns the day of the week named in the question
+func mentionedWeekday(question string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.Contains(question, strings.ToLower(day.String())) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// firstOccurrences keeps one event per recurring series, the earliest, so a
+// series-wide change is applied once
+func firstOccurrences(events []models.Task) []models.Task {
+	var result []models.Task
+	index := make(map[string]int)
+
+	for _, event := range events {
+		if event.RecurringEventID == "" {
+			result = append(result, event)
+			continue
+		}
+
+		i, seen := index[event.CalendarID+"/"+event.RecurringEventID]
+		if !seen {
+			index[event.CalendarID+"/"+event.RecurringEventID] = len(result)
+			result = append(result, event)
+		} else if event.Start < result[i].Start {
+			result[i] = event
+		}
+	}
+	return result
+}
+
+// describeDeletedEvent names a deleted event, saying which part of a series went
+func describeDeletedEvent(event models.Task, scope calendar.RecurrenceScope) string {
+	if event.RecurringEventID == "" {
+		return event.Summary
+	}
+
+	switch scope {
+	case calendar.ScopeSeries:
+		return fmt.Sprintf("%s (all occurrences)", event.Summary)
+	case calendar.ScopeFollowing:
+		return fmt.Sprintf("%s (from %s on)", event.Summary, utils.FormatDateTime(event.Start))
+	default:
+		return fmt.Sprintf("%s (%s only)", event.Summary, utils.FormatDateTime(event.Start))
+	}
+}
+
 // extractKeywords extracts relevant keywords from the delete request
 func (s *Server) extractKeywords(question string) []string {
 	question = strings.ToLower(question)
 	var keywords []string
 	
 	// Remove delete-related words to get the actual event keywords
-	deleteWords := []string{"delete", "remove", "cancel", "clear", "erase", "drop", "get rid of", "eliminate", "destroy", "wipe", "purge", "my", "the", "a", "an", "all", "today", "tomorrow", "yesterday"}
+	deleteWords := []string{"delete", "remove", "cancel", "clear", "erase", "drop", "get rid of", "eliminate", "destroy", "wipe", "purge", "skip", "my", "the", "a", "an", "all", "today", "tomorrow", "yesterday",
+		"just", "only", "this", "and", "future", "following", "from", "now", "onwards", "going", "forward", "remaining",
+		"series", "every", "entire", "whole", "recurring", "occurrence", "occurrences", "instance"}
 	
 	words := strings.Fields(question)
 	for _, word := range words {
@@ -430,11 +718,12 @@ func (s *Server) extractKeywords(question string) []string {
 }
 
 // handleViewFromQuery handles view/query requests
-func (s *Server) handleViewFromQuery(w http.ResponseWriter, question string) {
+func (s *Server) handleViewFromQuery(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest) {
+	question := req.Question
 	fmt.Printf("👀 Processing view request: %s\n", question)
 
 	// Process query - this should only return information, not create events
-	response, err := s.scheduler.GetQueryHandler().HandleQuery(context.Background(), question)
+	response, err := scheduler.GetQueryHandler().HandleQuery(context.Background(), question)
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -482,6 +771,46 @@ func (s *Server) handleViewFromQuery(w http.ResponseWriter, question string) {
 
 
 
+// handleListCalendars lists the calendars events can be read from and written to
+func (s *Server) handleListCalendars(w http.ResponseWriter, r *http.Request) {
+	calendarClient := s.scheduler.GetCalendarClient()
+
+	calendars, err := calendarClient.ListCalendars()
+	if err != nil {
+		fmt.Printf("❌ Failed to list calendars: %v\n", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to list calendars")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":          true,
+		"calendars":        calendars,
+		"default_calendar": calendarClient.GetCalendarID(),
+	})
+}
+
+// handleClassifyIntent reports how a unified query would be understood,
+// without acting on it
+func (s *Server) handleClassifyIntent(w http.ResponseWriter, r *http.Request) {
+	var req models.QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid JSON request")
+		return
+	}
+	if req.Question == "" {
+		s.writeError(w, http.StatusBadRequest, "question is required")
+		return
+	}
+
+	scheduler, err := s.schedulerFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, scheduler.GetIntentClassifier().Classify(req.Question))
+}
+
 // handleHealth handles health check requests
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, http.StatusOK, map[string]interface{}{
@@ -499,16 +828,76 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 		"version": "1.0.0",
 		"endpoints": map[string]interface{}{
 			"POST /api/unified": map[string]interface{}{
-				"description": "🚀 UNIFIED ENDPOINT - AI understands and performs create, view, and delete operations",
+				"description": "🚀 UNIFIED ENDPOINT - AI understands and performs create, view, update, and delete operations",
 				"body": map[string]string{
-					"question": "Natural language query (create/view/delete)",
+					"question":           "Natural language query (create/view/update/delete)",
+					"calendar_id":        "Optional calendar to use instead of the configured one",
+					"timezone":           "Optional IANA timezone, e.g. Europe/Berlin (defaults to APP_TIMEZONE)",
+					"scope":              "Optional for recurring events: instance, following or series (detected from the question otherwise)",
+					"dry_run":            "Optional; preview create and delete without changing the calendar",
+					"auto_resolve":       "Optional; book a new event that overlaps others at its closest free time instead",
+					"send_updates":       "Optional; how attendees hear about the change: all, externalOnly or none (defaults to SEND_UPDATES)",
+					"stream":             "Optional; send progress as Server-Sent Events (also: Accept: text/event-stream)",
+					"session_id":         "Optional; continues a conversation so follow-ups like \"make it 30 minutes instead\" work",
+					"confirmation_token": "Token from a planned delete; executes it (no question needed)",
+					"clarification_token": "Token from a clarify response; send with choice to resume the request (no question needed)",
+					"choice":              "ID of the chosen clarification option, e.g. 2 or all",
 				},
 				"examples": map[string]interface{}{
 					"create": "create a team meeting tomorrow at 2 PM",
 					"view":   "What's my schedule today?",
+					"update": "Move my gym session to 6 PM",
 					"delete": "Delete my gym session",
+					"undo":   "Undo that",
+				},
+			},
+			"PATCH /api/events/{id}": map[string]interface{}{
+				"description": "Update an event; fields left out are kept, and a new start keeps the original duration",
+				"body": map[string]string{
+					"summary":     "Optional new title",
+					"start":       "Optional new start (RFC3339)",
+					"end":         "Optional new end (RFC3339)",
+					"description": "Optional new description",
+					"location":    "Optional new location",
+					"calendar_id": "Optional calendar the event belongs to",
+					"timezone":    "Optional IANA timezone for the response",
+					"scope":       "For an occurrence of a recurring event: instance (default), following or series",
 				},
 			},
+			"POST /api/undo": map[string]interface{}{
+				"description": "Undo the most recent assistant-made changes (also: \"undo that\" on /api/unified)",
+				"body": map[string]string{
+					"count": "Optional number of changes to undo (default 1)",
+				},
+			},
+			"POST /api/intent": map[string]interface{}{
+				"description": "Classify a question the way /api/unified would, without acting on it",
+				"body": map[string]string{
+					"question": "Natural language query",
+					"timezone": "Optional IANA timezone used to resolve dates",
+				},
+			},
+			"GET /api/sessions/{id}": map[string]interface{}{
+				"description": "Show what a conversation remembers: its history, the events it is about and any open question",
+			},
+			"DELETE /api/sessions/{id}": map[string]interface{}{
+				"description": "Forget a conversation",
+			},
+			"GET /api/availability": map[string]interface{}{
+				"description": "Show the availability profile free time is found within",
+			},
+			"PUT /api/availability": map[string]interface{}{
+				"description": "Replace the availability profile",
+				"body": map[string]string{
+					"working_hours":     "Hours per lowercase weekday, e.g. {\"monday\": {\"start\": \"09:00\", \"end\": \"17:00\"}}; days left out are not worked",
+					"lunch":             "Optional {\"start\", \"end\"} kept free on working days",
+					"no_meeting_blocks": "Optional list of {\"name\", \"days\", \"start\", \"end\"} kept free",
+					"holidays":          "Optional list of {\"date\": \"YYYY-MM-DD\", \"name\"} with nothing booked",
+				},
+			},
+			"GET /api/calendars": map[string]interface{}{
+				"description": "List the calendars available for calendar_id",
+			},
 			"GET /health": map[string]interface{}{
 				"description": "Health check endpoint",
 			},
@@ -517,19 +906,29 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 
 	s.writeJSON(w, http.StatusOK, docs)
 }
-// isExplicitDeleteRequest checks if the question is explicitly asking to delete events
-func isExplicitDeleteRequest(question string) bool {
-	question = strings.ToLower(strings.TrimSpace(question))
-	
-	explicitDeleteKeywords := []string{
-		"delete", "remove", "cancel", "clear", "erase", "drop",
-		"get rid of", "eliminate", "destroy", "wipe", "purge",
+
+var (
+	// followingScopePattern marks a change to an occurrence and every later one
+	followingScopePattern = regexp.MustCompile(`\b(future|from now on|going forward|onwards?|and (all )?following|and after|remaining)\b`)
+
+	// seriesScopePattern marks a change to a whole recurring series
+	seriesScopePattern = regexp.MustCompile(`\b(series|every|all occurrences|entire|whole|recurring|altogether|for good|permanently)\b`)
+)
+
+// recurrenceScopeFor returns which occurrences of a recurring event a request
+// applies to: the explicit scope if given, otherwise one detected from the question
+func recurrenceScopeFor(req models.QueryRequest) (calendar.RecurrenceScope, error) {
+	if req.Scope != "" {
+		return calendar.ParseRecurrenceScope(req.Scope)
 	}
-	
-	for _, keyword := range explicitDeleteKeywords {
-		if strings.Contains(question, keyword) {
-			return true
-		}
+
+	question := strings.ToLower(req.Question)
+	if followingScopePattern.MatchString(question) {
+		return calendar.ScopeFollowing, nil
 	}
-	return false
+	if seriesScopePattern.MatchString(question) {
+		return calendar.ScopeSeries, nil
+	}
+
+	return calendar.ScopeInstance, nil
 }
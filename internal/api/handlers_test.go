@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+func TestRecurrenceScopeFor(t *testing.T) {
+	tests := []struct {
+		question string
+		want     calendar.RecurrenceScope
+	}{
+		{"cancel tomorrow's standup", calendar.ScopeInstance},
+		{"cancel every standup", calendar.ScopeSeries},
+		{"delete the whole series", calendar.ScopeSeries},
+		{"move standup to 10am from now on", calendar.ScopeFollowing},
+		{"drop all future standups", calendar.ScopeFollowing},
+		{"move the standup and all following to 10am", calendar.ScopeFollowing},
+
+		// Keywords inside other words don't set a scope
+		{"delete everything tomorrow", calendar.ScopeInstance},
+		{"move the standup with everyone to 3pm", calendar.ScopeInstance},
+		{"rename the standup to futures sync", calendar.ScopeInstance},
+		{"move the wholesale review to friday", calendar.ScopeInstance},
+		{"cancel the miniseries screening", calendar.ScopeInstance},
+	}
+
+	for _, test := range tests {
+		got, err := recurrenceScopeFor(models.QueryRequest{Question: test.question})
+		if err != nil {
+			t.Errorf("%q: %v", test.question, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: got %s, want %s", test.question, got, test.want)
+		}
+	}
+
+	if got, err := recurrenceScopeFor(models.QueryRequest{Question: "cancel every standup", Scope: "instance"}); err != nil || got != calendar.ScopeInstance {
+		t.Errorf("an explicit scope should win, got %s, %v", got, err)
+	}
+}
@@ -4,25 +4,31 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
 	"github.com/gorilla/mux"
-	calendarv3 "google.golang.org/api/calendar/v3"
 )
 
 // Server represents the API server
 type Server struct {
-	scheduler *planner.EnhancedScheduler
-	router    *mux.Router
+	scheduler      *planner.EnhancedScheduler
+	router         *mux.Router
+	confirmations  *confirmationStore
+	clarifications *confirmationStore
+	sessions       *sessionStore
 }
 
 // NewServer creates a new API server
-func NewServer(calendarService *calendarv3.Service, aiConfig models.AIConfig, timeZone string) *Server {
-	scheduler := planner.NewEnhancedScheduler(calendarService, aiConfig, timeZone)
+func NewServer(backend calendar.CalendarBackend, aiConfig models.AIConfig, calendarConfig models.CalendarConfig) *Server {
+	scheduler := planner.NewEnhancedScheduler(backend, aiConfig, calendarConfig)
 	
 	server := &Server{
-		scheduler: scheduler,
-		router:    mux.NewRouter(),
+		scheduler:      scheduler,
+		router:         mux.NewRouter(),
+		confirmations:  newConfirmationStore(),
+		clarifications: newConfirmationStore(),
+		sessions:       newSessionStore(),
 	}
 	
 	server.setupRoutes()
@@ -53,6 +59,14 @@ func (s *Server) setupRoutes() {
 	
 	// KEEP ONLY THIS:
 	api.HandleFunc("/unified", s.handleUnifiedQuery).Methods("POST")
+	api.HandleFunc("/calendars", s.handleListCalendars).Methods("GET")
+	api.HandleFunc("/events/{id}", s.handleUpdateEvent).Methods("PATCH", "OPTIONS")
+	api.HandleFunc("/undo", s.handleUndo).Methods("POST")
+	api.HandleFunc("/intent", s.handleClassifyIntent).Methods("POST")
+	api.HandleFunc("/sessions/{id}", s.handleGetSession).Methods("GET")
+	api.HandleFunc("/sessions/{id}", s.handleDeleteSession).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/availability", s.handleGetAvailability).Methods("GET")
+	api.HandleFunc("/availability", s.handlePutAvailability).Methods("PUT", "OPTIONS")
 	
 	// Health check
 	s.router.HandleFunc("/health", s.handleHealth).Methods("GET")
@@ -67,7 +81,7 @@ func (s *Server) setupRoutes() {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		
 		if r.Method == "OPTIONS" {
@@ -79,6 +93,16 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// schedulerFor returns the scheduler for a request's calendar, timezone and
+// way of telling attendees about changes
+func (s *Server) schedulerFor(req models.QueryRequest) (*planner.EnhancedScheduler, error) {
+	scheduler, err := s.scheduler.WithCalendar(req.CalendarID).WithTimeZone(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.WithSendUpdates(req.SendUpdates)
+}
+
 // writeJSON writes JSON response
 func (s *Server) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/gorilla/mux"
+)
+
+// sessionTTL is how long an idle conversation is remembered
+const sessionTTL = 30 * time.Minute
+
+// maxSessionTurns bounds the history kept per session, counting requests
+// and answers separately
+const maxSessionTurns = 20
+
+// maxSessionIDLength bounds client-chosen session IDs
+const maxSessionIDLength = 128
+
+// session is one conversation with the assistant
+type session struct {
+	conversation models.Conversation
+	lastUsed     time.Time
+}
+
+// sessionStore keeps conversations between /api/unified calls
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// newSessionStore creates an empty session store
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*session),
+	}
+}
+
+// conversation returns a copy of a session's conversation, or nil if the
+// session is new or has expired
+func (ss *sessionStore) conversation(id string) *models.Conversation {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	existing, exists := ss.sessions[id]
+	if !exists || time.Since(existing.lastUsed) > sessionTTL {
+		return nil
+	}
+
+	conversation := existing.conversation
+	conversation.History = append([]models.ConversationTurn(nil), existing.conversation.History...)
+	conversation.ReferencedEvents = append([]models.Task(nil), existing.conversation.ReferencedEvents...)
+	return &conversation
+}
+
+// record adds a request and the assistant's answer to a session. The events
+// of the answer become the ones follow-ups refer to, and a clarification
+// replaces any earlier one; an answer without one means the earlier
+// question was answered or abandoned.
+func (ss *sessionStore) record(id, question string, answer sessionAnswer) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range ss.sessions {
+		if now.Sub(existing.lastUsed) > sessionTTL {
+			delete(ss.sessions, key)
+		}
+	}
+
+	current, exists := ss.sessions[id]
+	if !exists {
+		current = &session{}
+		ss.sessions[id] = current
+	}
+	current.lastUsed = now
+
+	conversation := &current.conversation
+	conversation.History = append(conversation.History,
+		models.ConversationTurn{Role: "user", Content: question, Time: now},
+		models.ConversationTurn{Role: "assistant", Content: answer.text(), Time: now},
+	)
+	if len(conversation.History) > maxSessionTurns {
+		conversation.History = conversation.History[len(conversation.History)-maxSessionTurns:]
+	}
+
+	conversation.Clarification = answer.Clarification
+	if events := answer.optionEvents(); len(events) > 0 {
+		conversation.ReferencedEvents = events
+	} else if len(answer.Events) > 0 {
+		conversation.ReferencedEvents = answer.Events
+	}
+}
+
+// remove forgets a session
+func (ss *sessionStore) remove(id string) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	_, exists := ss.sessions[id]
+	delete(ss.sessions, id)
+	return exists
+}
+
+// sessionAnswer is the part of a /api/unified response a session remembers
+type sessionAnswer struct {
+	Answer        string                `json:"answer"`
+	Error         string                `json:"error"`
+	Events        []models.Task         `json:"events"`
+	Clarification *models.Clarification `json:"clarification"`
+}
+
+// text returns what the assistant said
+func (a sessionAnswer) text() string {
+	if a.Answer != "" {
+		return a.Answer
+	}
+	return a.Error
+}
+
+// optionEvents returns the events a clarifying question offers
+func (a sessionAnswer) optionEvents() []models.Task {
+	if a.Clarification == nil {
+		return nil
+	}
+
+	var events []models.Task
+	for _, option := range a.Clarification.Options {
+		if option.Event != nil {
+			events = append(events, *option.Event)
+		}
+	}
+	return events
+}
+
+// sessionRecorder passes a response through while keeping a copy for the session
+type sessionRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write copies and writes part of the response
+func (sr *sessionRecorder) Write(data []byte) (int, error) {
+	sr.body.Write(data)
+	return sr.ResponseWriter.Write(data)
+}
+
+// Unwrap returns the wrapped ResponseWriter
+func (sr *sessionRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// answer returns the recorded response
+func (sr *sessionRecorder) answer() sessionAnswer {
+	var answer sessionAnswer
+	if err := json.Unmarshal(sr.body.Bytes(), &answer); err != nil {
+		fmt.Printf("⚠️ Could not remember the answer for the session: %v\n", err)
+	}
+	return answer
+}
+
+// validateSessionID checks a client-chosen session ID
+func validateSessionID(id string) error {
+	if len(id) > maxSessionIDLength {
+		return fmt.Errorf("session_id must be at most %d characters", maxSessionIDLength)
+	}
+	return nil
+}
+
+// resolveClarification joins a reply to the request an open clarification
+// is about, so "the one at 3pm" is handled as part of "delete my meeting".
+// A reply that is clearly a request of its own is returned unchanged.
+func resolveClarification(scheduler *planner.EnhancedScheduler, conversation *models.Conversation, question string) string {
+	if conversation == nil || conversation.Clarification == nil {
+		return question
+	}
+	if scheduler.GetIntentClassifier().ClassifyWithRules(question).Confidence >= planner.RuleConfidenceThreshold {
+		return question
+	}
+	return fmt.Sprintf("%s (%s)", conversation.Clarification.Request, question)
+}
+
+// handleGetSession returns what a session remembers
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	conversation := s.sessions.conversation(mux.Vars(r)["id"])
+	if conversation == nil {
+		s.writeError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, conversation)
+}
+
+// handleDeleteSession forgets a session, so the next request starts a new conversation
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	if !s.sessions.remove(mux.Vars(r)["id"]) {
+		s.writeError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// slotAlternativeCount is how many other free times are offered alongside
+// the one an event was put in
+const slotAlternativeCount = 3
+
+// scheduleInFreeSlot puts a single new event whose request gave a length but
+// no time, like "find 45 minutes for a code review tomorrow afternoon", in
+// the best free slot instead of at the AI's guess. An event with attendees
+// goes in the best time everyone whose calendar can be checked is free; if
+// nobody's can, a request without a length is left to the caller. The
+// response lists the other free times too. It returns false, without
+// writing a response, when the request isn't one.
+func (s *Server) scheduleInFreeSlot(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, tasks []models.Task, existingTasks []models.Task) bool {
+	if len(tasks) != 1 || tasks[0].IsRecurring() {
+		return false
+	}
+	query, ok := scheduler.ParseSlotQuery(req.Question)
+	if !ok {
+		return false
+	}
+
+	task := tasks[0]
+	var unchecked []models.Attendee
+	if len(task.Attendees) > 0 {
+		busy, missing, err := scheduler.GetQueryService().GetAttendeesBusy(task.Attendees, query.Day, query.Day.AddDate(0, 0, query.Days))
+		if err != nil {
+			fmt.Printf("⚠️ Could not look up free/busy for %s: %v\n", task.Summary, err)
+		}
+		query.Busy = busy
+		unchecked = missing
+	}
+	everyone := checkedAttendees(task.Attendees, unchecked)
+	if query.Duration == 0 {
+		if len(everyone) == 0 {
+			return false
+		}
+		duration, err := task.Duration()
+		if err != nil || duration <= 0 {
+			duration = time.Hour
+		}
+		query.Duration = duration
+	}
+
+	slots, err := scheduler.FindSlots(query, slotAlternativeCount+1)
+	if err != nil {
+		fmt.Printf("⚠️ Could not search free time, using the AI's: %v\n", err)
+		return false
+	}
+
+	if len(slots) == 0 {
+		fmt.Printf("📭 No free %s slot %s\n", query.Duration, query)
+		answer := fmt.Sprintf("I couldn't find %s of free time for %s %s.", describeDuration(query.Duration), task.Summary, query)
+		if len(everyone) > 0 {
+			answer = fmt.Sprintf("I couldn't find %s when %s are all free %s.", describeDuration(query.Duration), utils.JoinNames(append([]string{"you"}, everyone...)), query)
+		}
+		if holiday, ok := scheduler.GetAvailability().Profile().HolidayOn(query.Day); ok && query.Days == 1 {
+			answer = fmt.Sprintf("%s is a holiday, so I didn't schedule %s.", query.Day.Format("Mon Jan 2"), task.Summary)
+			if holiday.Name != "" {
+				answer = fmt.Sprintf("%s is a holiday (%s), so I didn't schedule %s.", query.Day.Format("Mon Jan 2"), holiday.Name, task.Summary)
+			}
+		}
+		response := map[string]interface{}{
+			"answer":  answer,
+			"success": false,
+			"action":  "create",
+			"events":  []models.Task{},
+		}
+		noteUncheckedAttendees(response, unchecked)
+		s.writeJSON(w, http.StatusOK, response)
+		return true
+	}
+
+	location := scheduler.GetLocation()
+	var found []models.Task
+	for _, slot := range slots {
+		event := task
+		event.Start = slot.Start.In(location).Format(time.RFC3339)
+		event.End = slot.End.In(location).Format(time.RFC3339)
+		found = append(found, event)
+	}
+	chosen := found[0]
+	fmt.Printf("🔎 Best free slot %s: %s\n", query, chosen.Start)
+
+	verb := "I've scheduled"
+	if req.DryRun {
+		verb = "I would schedule"
+	}
+	best := "the best free time"
+	if len(everyone) > 0 {
+		best = "the best free time for " + utils.JoinNames(append([]string{"you"}, everyone...))
+	}
+	s.writeScheduleResult(w, scheduler, req, []models.Task{chosen}, existingTasks, map[string]interface{}{
+		"answer":       fmt.Sprintf("%s %s for %s - %s, %s %s.", verb, chosen.Summary, utils.FormatDateTime(chosen.Start), utils.FormatTime(chosen.End), best, query),
+		"alternatives": found[1:],
+	})
+	return true
+}
+
+// describeDuration spells out a duration, e.g. "45 minutes" or "1h30m"
+func describeDuration(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+	if d%time.Hour == 0 {
+		if d == time.Hour {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
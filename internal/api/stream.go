@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+)
+
+// Events sent while a streamed unified query runs
+const (
+	progressIntent       = "intent"        // what the request was understood as
+	progressCalendar     = "calendar"      // the existing events were fetched
+	progressToken        = "token"         // a piece of the AI's answer
+	progressPlan         = "plan"          // the AI's answer was understood
+	progressEventCreated = "event_created" // one event was created
+	progressAnswer       = "answer"        // the response /api/unified returns without streaming
+	progressError        = "error"         // the request failed
+)
+
+// eventStream sends the progress of a unified query as Server-Sent Events.
+// It stands in for the handler's ResponseWriter, so the JSON response a
+// handler writes is sent as the final answer or error event.
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	header  http.Header
+	status  int
+	mu      sync.Mutex
+}
+
+// newEventStream starts an event stream on w, if w can be flushed
+func newEventStream(w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // stop proxies buffering the stream
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &eventStream{
+		w:       w,
+		flusher: flusher,
+		header:  make(http.Header),
+		status:  http.StatusOK,
+	}, true
+}
+
+// Header returns headers that are ignored; the stream's were sent already
+func (es *eventStream) Header() http.Header {
+	return es.header
+}
+
+// WriteHeader notes the status of the handler's response
+func (es *eventStream) WriteHeader(status int) {
+	es.status = status
+}
+
+// Write sends the handler's JSON response as the answer event, or as the
+// error event for an error status
+func (es *eventStream) Write(body []byte) (int, error) {
+	event := progressAnswer
+	if es.status >= http.StatusBadRequest {
+		event = progressError
+	}
+	if err := es.send(event, bytes.TrimSpace(body)); err != nil {
+		return 0, err
+	}
+	return len(body), nil
+}
+
+// send writes one event with single-line JSON data
+func (es *eventStream) send(event string, data []byte) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if _, err := fmt.Fprintf(es.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	es.flusher.Flush()
+	return nil
+}
+
+// streamOf returns the event stream w writes to, looking through wrappers
+func streamOf(w http.ResponseWriter) (*eventStream, bool) {
+	for {
+		switch writer := w.(type) {
+		case *eventStream:
+			return writer, true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = writer.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// wantsEventStream checks whether a unified query asked for progress events
+func wantsEventStream(r *http.Request, req models.QueryRequest) bool {
+	return req.Stream || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// progress sends a progress event when w is an event stream, and does
+// nothing otherwise
+func (s *Server) progress(w http.ResponseWriter, event string, data interface{}) {
+	stream, ok := streamOf(w)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to encode %s event: %v\n", event, err)
+		return
+	}
+	if err := stream.send(event, body); err != nil {
+		fmt.Printf("⚠️ Failed to send %s event: %v\n", event, err)
+	}
+}
+
+// generatePlan asks the AI for a plan. On an event stream the answer is
+// sent as token events while it is generated.
+func (s *Server) generatePlan(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, prompt string) (string, error) {
+	if _, ok := streamOf(w); !ok {
+		return scheduler.GetAIManager().GeneratePlan(prompt)
+	}
+
+	return scheduler.GetAIManager().StreamPlan(prompt, func(delta string) {
+		s.progress(w, progressToken, map[string]string{"text": delta})
+	})
+}
+
+// toolIntent returns the intent a calendar tool carries out
+func toolIntent(tool string) string {
+	switch tool {
+	case toolCreateEvent:
+		return planner.IntentCreate
+	case toolUpdateEvent:
+		return planner.IntentUpdate
+	case toolDeleteEvent:
+		return planner.IntentDelete
+	case toolUndo:
+		return planner.IntentUndo
+	}
+	return planner.IntentView
+}
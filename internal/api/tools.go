@@ -0,0 +1,427 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/ai"
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// Calendar tools offered to the model
+const (
+	toolCreateEvent   = "create_event"
+	toolListEvents    = "list_events"
+	toolUpdateEvent   = "update_event"
+	toolDeleteEvent   = "delete_event"
+	toolUndo          = "undo"
+	toolQueryCalendar = "query_calendar"
+)
+
+// scopeParameter is the JSON schema of a recurrence scope argument
+var scopeParameter = map[string]interface{}{
+	"type":        "string",
+	"enum":        []string{"instance", "following", "series"},
+	"description": "For an occurrence of a recurring event: only this one (instance, default), this and later ones (following) or all of them (series)",
+}
+
+// remindersParameter is the JSON schema of a list of reminders
+var remindersParameter = map[string]interface{}{
+	"type": "array",
+	"items": map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"method":  map[string]interface{}{"type": "string", "enum": []string{models.ReminderPopup, models.ReminderEmail}},
+			"minutes": map[string]interface{}{"type": "integer", "description": "Minutes before the start"},
+		},
+		"required": []string{"method", "minutes"},
+	},
+	"description": "Only when the user asks to be reminded, e.g. \"remind me 30 minutes before\"",
+}
+
+// calendarTools are the tools the model can call to handle a unified query
+var calendarTools = []ai.Tool{
+	{
+		Name:        toolCreateEvent,
+		Description: "Create one or more new calendar events the user explicitly asked for",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"events": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"summary": map[string]interface{}{"type": "string", "description": "Event title"},
+							"start":   map[string]interface{}{"type": "string", "description": "Start time, RFC3339 with UTC offset"},
+							"end":     map[string]interface{}{"type": "string", "description": "End time, RFC3339 with UTC offset"},
+							"recurrence": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "Only for repeating events: one RRULE string, e.g. RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+							},
+							"attendees": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"name":  map[string]interface{}{"type": "string"},
+										"email": map[string]interface{}{"type": "string", "description": "Only if the user gave it"},
+									},
+								},
+								"description": "Only when the user names people the event is with",
+							},
+							"location":    map[string]interface{}{"type": "string", "description": "Only when the user says where the event is"},
+							"description": map[string]interface{}{"type": "string", "description": "Only when the user gives notes or an agenda"},
+							"conference": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"create": map[string]interface{}{"type": "boolean"},
+								},
+								"description": "Only when the user asks for a video call: {\"create\": true}",
+							},
+							"reminders": remindersParameter,
+						},
+						"required": []string{"summary", "start", "end"},
+					},
+				},
+			},
+			"required": []string{"events"},
+		},
+	},
+	{
+		Name:        toolListEvents,
+		Description: "List the events between two dates, optionally only those whose title contains a keyword",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"start_date": map[string]interface{}{"type": "string", "description": "First day, YYYY-MM-DD"},
+				"end_date":   map[string]interface{}{"type": "string", "description": "Last day (inclusive), YYYY-MM-DD"},
+				"keyword":    map[string]interface{}{"type": "string", "description": "Optional word the title must contain"},
+			},
+			"required": []string{"start_date", "end_date"},
+		},
+	},
+	{
+		Name:        toolUpdateEvent,
+		Description: "Move, reschedule or rename ONE existing event, or set its reminders",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"event_id":  map[string]interface{}{"type": "string", "description": "ID of the event, copied from the event list"},
+				"summary":   map[string]interface{}{"type": "string", "description": "New title, or empty to keep it"},
+				"start":     map[string]interface{}{"type": "string", "description": "New start time, RFC3339, or empty to keep it"},
+				"end":       map[string]interface{}{"type": "string", "description": "New end time, RFC3339, or empty to keep the duration"},
+				"reminders": remindersParameter,
+				"scope":     scopeParameter,
+			},
+			"required": []string{"event_id"},
+		},
+	},
+	{
+		Name:        toolDeleteEvent,
+		Description: "Delete, cancel or skip existing events. The user confirms before anything is deleted.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"event_ids": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "IDs of the events, copied from the event list",
+				},
+				"scope": scopeParameter,
+			},
+			"required": []string{"event_ids"},
+		},
+	},
+	{
+		Name:        toolUndo,
+		Description: "Undo the most recent changes the assistant made",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"count": map[string]interface{}{"type": "integer", "description": "Number of changes to undo, default 1"},
+			},
+		},
+	},
+	{
+		Name:        toolQueryCalendar,
+		Description: "Answer any other question about the schedule, such as free time, the next meeting or when an event is",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	},
+}
+
+// createEventArgs are the arguments of create_event
+type createEventArgs struct {
+	Events []models.Task `json:"events"`
+}
+
+// listEventsArgs are the arguments of list_events
+type listEventsArgs struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Keyword   string `json:"keyword,omitempty"`
+}
+
+// updateEventArgs are the arguments of update_event
+type updateEventArgs struct {
+	EventID   string            `json:"event_id"`
+	Summary   string            `json:"summary,omitempty"`
+	Start     string            `json:"start,omitempty"`
+	End       string            `json:"end,omitempty"`
+	Reminders []models.Reminder `json:"reminders,omitempty"`
+	Scope     string            `json:"scope,omitempty"`
+}
+
+// deleteEventArgs are the arguments of delete_event
+type deleteEventArgs struct {
+	EventIDs []string `json:"event_ids"`
+	Scope    string   `json:"scope,omitempty"`
+}
+
+// undoArgs are the arguments of undo
+type undoArgs struct {
+	Count int `json:"count,omitempty"`
+}
+
+// handleWithTools lets the model pick the action for a unified query by
+// calling a calendar tool, and runs it. It returns false without writing a
+// response if the model could not be asked, so the caller can fall back to
+// keyword routing.
+func (s *Server) handleWithTools(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest) bool {
+	events := candidateEvents(scheduler)
+	s.progress(w, progressCalendar, map[string]int{"events": len(events)})
+
+	prompt := scheduler.GetPromptGenerator().CreateToolPrompt(events, req.Question)
+	calls, err := scheduler.GetAIManager().CallTools(prompt, calendarTools)
+	if err != nil {
+		fmt.Printf("⚠️ Tool calling failed, falling back to keyword routing: %v\n", err)
+		return false
+	}
+
+	call := calls[0]
+	fmt.Printf("🧰 AI called %s: %s\n", call.Name, string(call.Arguments))
+	s.progress(w, progressIntent, map[string]interface{}{
+		"intent":    toolIntent(call.Name),
+		"source":    "tools",
+		"tool":      call.Name,
+		"arguments": call.Arguments,
+	})
+
+	// Several create_event calls are one request for several events
+	if call.Name == toolCreateEvent {
+		var tasks []models.Task
+		for _, c := range calls {
+			if c.Name != toolCreateEvent {
+				continue
+			}
+			var args createEventArgs
+			if !s.decodeToolArguments(w, c, &args) {
+				return true
+			}
+			tasks = append(tasks, args.Events...)
+		}
+		s.progress(w, progressPlan, map[string]interface{}{"events": tasks})
+		s.runCreateEvent(w, scheduler, req, tasks, events)
+		return true
+	}
+	if len(calls) > 1 {
+		fmt.Printf("⚠️ Ignoring %d further tool call(s)\n", len(calls)-1)
+	}
+
+	switch call.Name {
+	case toolListEvents:
+		var args listEventsArgs
+		if s.decodeToolArguments(w, call, &args) {
+			s.runListEvents(w, scheduler, args)
+		}
+	case toolUpdateEvent:
+		var args updateEventArgs
+		if s.decodeToolArguments(w, call, &args) {
+			s.runUpdateEvent(w, scheduler, req, args, events)
+		}
+	case toolDeleteEvent:
+		var args deleteEventArgs
+		if s.decodeToolArguments(w, call, &args) {
+			s.runDeleteEvent(w, scheduler, req, args, events)
+		}
+	case toolUndo:
+		var args undoArgs
+		if s.decodeToolArguments(w, call, &args) {
+			if args.Count <= 0 {
+				args.Count = 1
+			}
+			s.writeUndoResult(w, scheduler, args.Count)
+		}
+	default:
+		s.handleViewFromQuery(w, scheduler, req)
+	}
+	return true
+}
+
+// decodeToolArguments parses the arguments of a tool call, writing an error
+// response if they are invalid
+func (s *Server) decodeToolArguments(w http.ResponseWriter, call ai.ToolCall, args interface{}) bool {
+	if err := json.Unmarshal(call.Arguments, args); err != nil {
+		fmt.Printf("❌ Invalid %s arguments: %v\n", call.Name, err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to understand your request. Please be more specific.")
+		return false
+	}
+	return true
+}
+
+// candidateEvents returns today's and the next 7 days' events, which the
+// model can refer to by ID
+func candidateEvents(scheduler *planner.EnhancedScheduler) []models.Task {
+	candidates, err := scheduler.GetQueryService().GetUpcomingEvents(7)
+	if err != nil {
+		candidates = []models.Task{}
+	}
+	todaysEvents, err := scheduler.GetQueryService().GetTodaysSchedule()
+	if err == nil {
+		candidates = mergeEvents(todaysEvents, candidates)
+	}
+	return candidates
+}
+
+// findCandidate returns the candidate event with the given ID
+func findCandidate(events []models.Task, eventID string) *models.Task {
+	for i := range events {
+		if events[i].EventID == eventID {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// runCreateEvent handles create_event
+func (s *Server) runCreateEvent(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, tasks []models.Task, existingTasks []models.Task) {
+	for i, task := range tasks {
+		if err := task.ValidateTask(); err != nil {
+			fmt.Printf("❌ Invalid event %d from AI: %v\n", i+1, err)
+			s.writeError(w, http.StatusInternalServerError, "Failed to understand your request. Please be more specific.")
+			return
+		}
+	}
+
+	tasks = scheduler.AddAttendees(tasks, req.Question)
+	if s.scheduleInFreeSlot(w, scheduler, req, tasks, existingTasks) || s.clarifySchedule(w, scheduler, req, tasks, existingTasks) {
+		return
+	}
+	s.writeScheduleResult(w, scheduler, req, tasks, existingTasks, nil)
+}
+
+// runListEvents handles list_events
+func (s *Server) runListEvents(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, args listEventsArgs) {
+	location := scheduler.GetCalendarClient().Location()
+	startDate, err := time.ParseInLocation("2006-01-02", args.StartDate, location)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid start_date %q", args.StartDate))
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", args.EndDate, location)
+	if err != nil || endDate.Before(startDate) {
+		endDate = startDate
+	}
+
+	events, err := scheduler.GetQueryService().GetEventsByDateRange(startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if keyword := strings.ToLower(strings.TrimSpace(args.Keyword)); keyword != "" {
+		var matching []models.Task
+		for _, event := range events {
+			if strings.Contains(strings.ToLower(event.Summary), keyword) {
+				matching = append(matching, event)
+			}
+		}
+		events = matching
+	}
+
+	period := startDate.Format("Mon Jan 2")
+	if !endDate.Equal(startDate) {
+		period += " to " + endDate.Format("Mon Jan 2")
+	}
+
+	var answer string
+	if len(events) == 0 {
+		answer = fmt.Sprintf("You have no events on %s.", period)
+	} else {
+		answer = fmt.Sprintf("You have %d event(s) on %s:", len(events), period)
+		for i, event := range events {
+			answer += fmt.Sprintf("\n%d. %s at %s%s", i+1, event.Summary, utils.FormatDateTime(event.Start), utils.ReminderNote(event))
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"answer":  answer,
+		"events":  events,
+		"success": true,
+		"action":  "view",
+	})
+}
+
+// runUpdateEvent handles update_event
+func (s *Server) runUpdateEvent(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, args updateEventArgs, events []models.Task) {
+	original := findCandidate(events, args.EventID)
+	if original == nil {
+		s.writeNoUpdateMatch(w, req)
+		return
+	}
+
+	// An explicit scope in the request wins over the model's
+	if req.Scope == "" {
+		req.Scope = args.Scope
+	}
+	scope, err := recurrenceScopeFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, reminder := range args.Reminders {
+		if err := reminder.Validate(); err != nil {
+			fmt.Printf("❌ Invalid reminder from AI: %v\n", err)
+			s.writeError(w, http.StatusInternalServerError, "Failed to understand your request. Please be more specific.")
+			return
+		}
+	}
+
+	change := models.Task{EventID: args.EventID, Summary: args.Summary, Start: args.Start, End: args.End, Reminders: args.Reminders}
+	s.writeUpdateResult(w, scheduler, *original, change, scope)
+}
+
+// runDeleteEvent handles delete_event
+func (s *Server) runDeleteEvent(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest, args deleteEventArgs, events []models.Task) {
+	var eventsToDelete []models.Task
+	for _, eventID := range args.EventIDs {
+		if event := findCandidate(events, eventID); event != nil {
+			eventsToDelete = append(eventsToDelete, *event)
+		}
+	}
+
+	if req.Scope == "" {
+		req.Scope = args.Scope
+	}
+	scope, err := recurrenceScopeFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if scope != calendar.ScopeInstance {
+		eventsToDelete = firstOccurrences(eventsToDelete)
+	}
+
+	s.writeDeletePlan(w, scheduler, req, eventsToDelete, scope)
+}
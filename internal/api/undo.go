@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+)
+
+// maxUndoCount bounds how many changes one undo request can reverse
+const maxUndoCount = 50
+
+// UndoRequest represents a request to undo recent changes
+type UndoRequest struct {
+	Count int `json:"count,omitempty"` // number of changes to undo, default 1
+}
+
+var undoCountPattern = regexp.MustCompile(`\b(\d+)\b`)
+
+var undoCountWords = map[string]int{
+	"two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// undoCount extracts how many changes to undo from the question, e.g.
+// "undo the last 3 changes"; "undo that" means one
+func undoCount(question string) int {
+	question = strings.ToLower(question)
+
+	if match := undoCountPattern.FindStringSubmatch(question); match != nil {
+		if n, err := strconv.Atoi(match[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+	for _, word := range strings.Fields(question) {
+		if n, ok := undoCountWords[word]; ok {
+			return n
+		}
+	}
+	return 1
+}
+
+// undoHint tells the user how to reverse the last count changes
+func undoHint(count int) string {
+	if count == 1 {
+		return `. Say "undo" to reverse this`
+	}
+	return fmt.Sprintf(`. Say "undo %d" to reverse this`, count)
+}
+
+// handleUndo handles POST /api/undo
+func (s *Server) handleUndo(w http.ResponseWriter, r *http.Request) {
+	var req UndoRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "Invalid JSON request")
+			return
+		}
+	}
+	if req.Count < 0 {
+		s.writeError(w, http.StatusBadRequest, "count must be positive")
+		return
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+
+	s.writeUndoResult(w, s.scheduler, req.Count)
+}
+
+// handleUndoFromQuery handles "undo that" on the unified endpoint
+func (s *Server) handleUndoFromQuery(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, question string) {
+	fmt.Printf("↩️ Processing undo request: %s\n", question)
+	s.writeUndoResult(w, scheduler, undoCount(question))
+}
+
+// writeUndoResult undoes the last count changes and writes what was reversed
+func (s *Server) writeUndoResult(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, count int) {
+	if count > maxUndoCount {
+		count = maxUndoCount
+	}
+
+	undone, err := scheduler.GetCalendarClient().Undo(count)
+	if err != nil && len(undone) == 0 {
+		fmt.Printf("❌ Undo failed: %v\n", err)
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"answer":  fmt.Sprintf("I couldn't undo that: %v", err),
+			"success": false,
+			"action":  "undo",
+		})
+		return
+	}
+
+	if len(undone) == 0 {
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"answer":  "There is nothing to undo.",
+			"success": false,
+			"action":  "undo",
+		})
+		return
+	}
+
+	// Entries of one change, such as a split-off occurrence and its
+	// series, count once
+	var changes []string
+	changeIDs := make(map[int]bool)
+	for _, entry := range undone {
+		changes = append(changes, describeUndo(entry))
+		changeIDs[entry.ChangeID()] = true
+	}
+	answer := fmt.Sprintf("Undid %d change(s): %s.", len(changeIDs), strings.Join(changes, ", "))
+	if err != nil {
+		answer += fmt.Sprintf(" Stopped early: %v", err)
+	}
+
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"answer":  answer,
+		"success": true,
+		"action":  "undo",
+		"undone":  undone,
+	})
+}
+
+// describeUndo says what undoing a journal entry did
+func describeUndo(entry calendar.JournalEntry) string {
+	switch entry.Operation {
+	case calendar.OperationCreate:
+		return fmt.Sprintf("removed %s", entry.Event.Summary)
+	case calendar.OperationDelete:
+		return fmt.Sprintf("restored %s", entry.Event.Summary)
+	default:
+		summary := entry.Event.Summary
+		if entry.Previous != nil && entry.Previous.Summary != "" {
+			summary = entry.Previous.Summary
+		}
+		return fmt.Sprintf("reverted changes to %s", summary)
+	}
+}
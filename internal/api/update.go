@@ -0,0 +1,364 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"github.com/Karan2980/llm-planner-golang-project/internal/planner"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// UpdateRequest represents a direct event update request
+type UpdateRequest struct {
+	Summary     string `json:"summary,omitempty"`
+	Start       string `json:"start,omitempty"`
+	End         string `json:"end,omitempty"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+	CalendarID  string `json:"calendar_id,omitempty"`
+	TimeZone    string `json:"timezone,omitempty"`
+	Scope       string `json:"scope,omitempty"` // instance, following or series for recurring events
+
+	Attendees   []models.Attendee  `json:"attendees,omitempty"`    // replaces the guest list
+	Conference  *models.Conference `json:"conference,omitempty"`   // {"create": true} adds a video call
+	SendUpdates string             `json:"send_updates,omitempty"` // all, externalOnly or none
+	Reminders   []models.Reminder  `json:"reminders,omitempty"`    // replaces the event's reminders
+}
+
+// handleUpdateFromQuery handles move/rename requests from natural language
+func (s *Server) handleUpdateFromQuery(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, req models.QueryRequest) {
+	question := req.Question
+	fmt.Printf("✏️ Processing update request: %s\n", question)
+
+	// Get candidate events
+	candidates := candidateEvents(scheduler)
+	s.progress(w, progressCalendar, map[string]int{"events": len(candidates)})
+
+	if len(candidates) == 0 {
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"answer":  "You have no events to change.",
+			"success": false,
+			"action":  "update",
+		})
+		return
+	}
+
+	// Ask AI which event to change and how
+	prompt := scheduler.GetPromptGenerator().CreateUpdatePrompt(candidates, question)
+	changeJSON, err := s.generatePlan(w, scheduler, prompt)
+	if err != nil {
+		fmt.Printf("❌ AI update planning failed: %v\n", err)
+		s.writeError(w, http.StatusInternalServerError, "AI service unavailable. Please try again later.")
+		return
+	}
+
+	fmt.Printf("✅ AI Generated change: %s\n", changeJSON)
+
+	change, err := utils.ParseEventChange(changeJSON)
+	if err != nil {
+		fmt.Printf("❌ Failed to parse AI response: %v\n", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to understand your request. Please be more specific.")
+		return
+	}
+	s.progress(w, progressPlan, map[string]interface{}{"change": change})
+
+	var original *models.Task
+	for i := range candidates {
+		if candidates[i].EventID == change.EventID {
+			original = &candidates[i]
+			break
+		}
+	}
+	if original == nil {
+		s.writeNoUpdateMatch(w, req)
+		return
+	}
+
+	scope, err := recurrenceScopeFor(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.writeUpdateResult(w, scheduler, *original, change, scope)
+}
+
+// writeNoUpdateMatch asks the user which event a change is about
+func (s *Server) writeNoUpdateMatch(w http.ResponseWriter, req models.QueryRequest) {
+	answer := "No matching event found to change. Please be more specific."
+	s.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"answer":        answer,
+		"success":       false,
+		"action":        "update",
+		"clarification": &models.Clarification{Question: answer, Request: req.Question},
+	})
+}
+
+// handleUpdateEvent handles PATCH /api/events/{id}
+func (s *Server) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid JSON request")
+		return
+	}
+
+	scheduler, err := s.schedulerFor(models.QueryRequest{CalendarID: req.CalendarID, TimeZone: req.TimeZone, SendUpdates: req.SendUpdates})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scope, err := calendar.ParseRecurrenceScope(req.Scope)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, reminder := range req.Reminders {
+		if err := reminder.Validate(); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	eventID := mux.Vars(r)["id"]
+	original, err := scheduler.GetCalendarClient().GetEvent(eventID)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	change := models.Task{
+		EventID:     eventID,
+		Summary:     req.Summary,
+		Start:       req.Start,
+		End:         req.End,
+		Description: req.Description,
+		Location:    req.Location,
+		Attendees:   req.Attendees,
+		Conference:  req.Conference,
+		Reminders:   req.Reminders,
+	}
+
+	s.writeUpdateResult(w, scheduler, *original, change, scope)
+}
+
+// writeUpdateResult applies a change to an event and writes the before and
+// after versions, or the events the new time would conflict with. For an
+// occurrence of a recurring event, scope selects which occurrences change;
+// only the edited occurrence is checked for conflicts.
+func (s *Server) writeUpdateResult(w http.ResponseWriter, scheduler *planner.EnhancedScheduler, original models.Task, change models.Task, scope calendar.RecurrenceScope) {
+	updated, err := applyChange(original, change)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Validate the new slot against the other events on those days
+	if updated.Start != original.Start || updated.End != original.End {
+		conflicts, err := s.findConflicts(scheduler, updated)
+		if err != nil {
+			fmt.Printf("⚠️ Could not check conflicts: %v\n", err)
+		}
+		if len(conflicts) > 0 {
+			var names []string
+			for _, conflict := range conflicts {
+				names = append(names, fmt.Sprintf("%s (%s)", conflict.Summary, utils.FormatDateTime(conflict.Start)))
+			}
+			s.writeJSON(w, http.StatusOK, map[string]interface{}{
+				"answer":    fmt.Sprintf("I can't move %s to %s because it overlaps with %s.", original.Summary, utils.FormatDateTime(updated.Start), strings.Join(names, ", ")),
+				"success":   false,
+				"action":    "update",
+				"before":    original,
+				"conflicts": conflicts,
+			})
+			return
+		}
+	}
+
+	eventClient := scheduler.GetCalendarClient().WithCalendar(original.CalendarID)
+
+	var after *models.Task
+	if original.RecurringEventID != "" {
+		after, err = eventClient.UpdateRecurringEvent(original, updated, scope)
+	} else {
+		// Only attendees the change sets are written back, so a rename or
+		// move leaves the calendar's attendee list alone
+		patch := updated
+		if len(change.Attendees) == 0 {
+			patch.Attendees = nil
+		}
+		after, err = eventClient.UpdateEvent(patch)
+	}
+	if errors.Is(err, calendar.ErrEventChanged) {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("%s was changed in the calendar meanwhile; fetch it again and retry", original.Summary))
+		return
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to update event: %v\n", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to update event")
+		return
+	}
+
+	response := map[string]interface{}{
+		"answer":  describeUpdate(original, *after),
+		"success": true,
+		"action":  "update",
+		"before":  original,
+		"after":   after,
+		"events":  []models.Task{*after},
+	}
+	if original.RecurringEventID != "" {
+		response["scope"] = scope
+	}
+
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// findConflicts returns the events, other than the event itself, that
+// overlap a new or updated event
+func (s *Server) findConflicts(scheduler *planner.EnhancedScheduler, updated models.Task) ([]models.Task, error) {
+	others, err := s.eventsAround(scheduler, updated)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.GetConflictChecker().ConflictingEvents(updated, others), nil
+}
+
+// eventsAround returns the events, other than the event itself, on the
+// days a new or updated event spans
+func (s *Server) eventsAround(scheduler *planner.EnhancedScheduler, event models.Task) ([]models.Task, error) {
+	parsed, err := event.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+
+	location := scheduler.GetLocation()
+	start := parsed.StartTime.In(location)
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+
+	events, err := scheduler.GetQueryService().GetEventsByDateRange(dayStart, parsed.EndTime.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	var others []models.Task
+	for _, other := range events {
+		if event.EventID == "" || other.EventID != event.EventID {
+			others = append(others, other)
+		}
+	}
+	return others, nil
+}
+
+// applyChange overlays the non-empty fields of change on the original event.
+// A new start without a new end keeps the original duration.
+func applyChange(original models.Task, change models.Task) (models.Task, error) {
+	updated := original
+
+	if change.Summary != "" {
+		updated.Summary = change.Summary
+	}
+	if change.Description != "" {
+		updated.Description = change.Description
+	}
+	if change.Location != "" {
+		updated.Location = change.Location
+	}
+	if len(change.Attendees) > 0 {
+		updated.Attendees = change.Attendees
+	}
+	if change.Conference != nil {
+		updated.Conference = change.Conference
+	}
+	if len(change.Reminders) > 0 {
+		updated.Reminders = change.Reminders
+	}
+
+	if change.Start != "" {
+		newStart, err := time.Parse(time.RFC3339, change.Start)
+		if err != nil {
+			return updated, fmt.Errorf("invalid start time: %v", err)
+		}
+		updated.Start = change.Start
+
+		if change.End == "" {
+			duration, err := original.Duration()
+			if err != nil {
+				duration = time.Hour
+			}
+			updated.End = newStart.Add(duration).Format(time.RFC3339)
+		}
+	}
+	if change.End != "" {
+		updated.End = change.End
+	}
+
+	parsed, err := updated.ParseTime()
+	if err != nil {
+		return updated, err
+	}
+	if !parsed.EndTime.After(parsed.StartTime) {
+		return updated, fmt.Errorf("event must end after it starts")
+	}
+
+	return updated, nil
+}
+
+// describeUpdate summarizes what changed between two versions of an event
+func describeUpdate(before, after models.Task) string {
+	var changes []string
+	if before.Summary != after.Summary {
+		changes = append(changes, fmt.Sprintf("renamed it to %s", after.Summary))
+	}
+	if before.Start != after.Start || before.End != after.End {
+		changes = append(changes, fmt.Sprintf("moved it to %s - %s", utils.FormatDateTime(after.Start), utils.FormatTime(after.End)))
+	}
+	if before.Location != after.Location {
+		changes = append(changes, fmt.Sprintf("set the location to %s", after.Location))
+	}
+	if before.Description != after.Description {
+		changes = append(changes, "updated the description")
+	}
+	if !reflect.DeepEqual(before.Attendees, after.Attendees) {
+		changes = append(changes, "updated the guest list")
+	}
+	if !reflect.DeepEqual(before.Conference, after.Conference) {
+		changes = append(changes, "updated the video call")
+	}
+	if !reflect.DeepEqual(before.Reminders, after.Reminders) {
+		changes = append(changes, fmt.Sprintf("set reminders: %s", utils.DescribeReminders(after.Reminders)))
+	}
+
+	if len(changes) == 0 {
+		return fmt.Sprintf("%s is unchanged.", before.Summary)
+	}
+	return fmt.Sprintf("Updated %s: %s.", before.Summary, strings.Join(changes, ", "))
+}
+
+// mergeEvents appends events not already present, matching by ID or by summary and start
+func mergeEvents(events []models.Task, more []models.Task) []models.Task {
+	merged := append([]models.Task{}, events...)
+	for _, event := range more {
+		found := false
+		for _, existing := range merged {
+			if (event.EventID != "" && existing.EventID == event.EventID && existing.Start == event.Start) ||
+				(existing.Summary == event.Summary && existing.Start == event.Start) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, event)
+		}
+	}
+	return merged
+}
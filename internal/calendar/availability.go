@@ -0,0 +1,97 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// Availability holds the user's availability profile and saves changes to
+// a file. A nil Availability has the default profile.
+type Availability struct {
+	mu      sync.RWMutex
+	path    string
+	profile models.AvailabilityProfile
+}
+
+// OpenAvailability loads the profile stored at path, or starts with the
+// default profile if the file does not exist yet. With an empty path
+// changes are kept in memory only.
+func OpenAvailability(path string) (*Availability, error) {
+	availability := &Availability{path: path, profile: models.DefaultAvailabilityProfile()}
+	if path == "" {
+		return availability, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return availability, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read availability profile: %v", err)
+	}
+
+	var profile models.AvailabilityProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("unable to parse availability profile: %v", err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid availability profile %s: %v", path, err)
+	}
+	availability.profile = profile
+
+	return availability, nil
+}
+
+// Profile returns the current profile
+func (a *Availability) Profile() models.AvailabilityProfile {
+	if a == nil {
+		return models.DefaultAvailabilityProfile()
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.profile
+}
+
+// SetProfile validates, replaces and saves the profile
+func (a *Availability) SetProfile(profile models.AvailabilityProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.path != "" {
+		if err := a.save(profile); err != nil {
+			return err
+		}
+	}
+	a.profile = profile
+	return nil
+}
+
+// save writes a profile atomically. The caller must hold a.mu.
+func (a *Availability) save(profile models.AvailabilityProfile) error {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(a.path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("unable to create availability profile directory: %v", err)
+		}
+	}
+
+	tmp := a.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write availability profile: %v", err)
+	}
+	return os.Rename(tmp, a.path)
+}
@@ -0,0 +1,33 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// CalendarBackend is the set of calendar operations the assistant relies on.
+// Google Calendar is one implementation; others can be plugged in behind Client.
+type CalendarBackend interface {
+	// ListEvents returns events overlapping [timeMin, timeMax), ordered by start time
+	ListEvents(calendarID string, timeMin, timeMax time.Time) ([]models.Task, error)
+	// GetEvent returns a single event by ID. Here and in InsertEvent and
+	// UpdateEvent, all-day and floating times are placed in loc.
+	GetEvent(calendarID, eventID string, loc *time.Location) (*models.Task, error)
+	// InsertEvent creates an event and returns it with its EventID set
+	InsertEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error)
+	// UpdateEvent updates the event identified by task.EventID
+	UpdateEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error)
+	// ReplaceEvent overwrites the event identified by task.EventID with
+	// task, clearing the fields task leaves empty
+	ReplaceEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error)
+	// DeleteEvent deletes an event by ID
+	DeleteEvent(calendarID, eventID string) error
+	// ListCalendars returns the calendars the user has access to
+	ListCalendars() ([]models.CalendarInfo, error)
+	// FreeBusy returns the busy times in [timeMin, timeMax) of each calendar
+	// or person, by email, that it could look up; the others are left out
+	FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error)
+	// GetName returns the backend name
+	GetName() string
+}
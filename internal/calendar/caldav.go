@@ -0,0 +1,616 @@
+package calendar
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// ErrEventChanged reports that an event was changed by someone else between
+// reading and writing it
+var ErrEventChanged = errors.New("event was changed by someone else")
+
+// CalDAVBackend implements CalendarBackend against a CalDAV server
+// such as Nextcloud or Radicale
+type CalDAVBackend struct {
+	homeURL         string // calendar home collection, ending in "/"
+	defaultCalendar string
+	username        string
+	password        string
+	httpClient      *http.Client
+}
+
+// NewCalDAVBackend creates a new CalDAV backend. A nil httpClient uses a
+// client with a 30 second timeout.
+func NewCalDAVBackend(config models.CalDAVConfig, httpClient *http.Client) (*CalDAVBackend, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("missing CalDAV URL. Please set CALDAV_URL in .env file")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid CalDAV URL: %v", err)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	defaultCalendar := config.Calendar
+	if defaultCalendar == "" {
+		defaultCalendar = "personal"
+	}
+
+	return &CalDAVBackend{
+		homeURL:         strings.TrimSuffix(config.URL, "/") + "/",
+		defaultCalendar: defaultCalendar,
+		username:        config.Username,
+		password:        config.Password,
+		httpClient:      httpClient,
+	}, nil
+}
+
+// GetName returns the backend name
+func (c *CalDAVBackend) GetName() string {
+	return "CalDAV"
+}
+
+// ListEvents runs a calendar-query REPORT with recurring events expanded
+func (c *CalDAVBackend) ListEvents(calendarID string, timeMin, timeMax time.Time) ([]models.Task, error) {
+	start := timeMin.UTC().Format("20060102T150405Z")
+	end := timeMax.UTC().Format("20060102T150405Z")
+
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag/>
+    <C:calendar-data>
+      <C:expand start="%s" end="%s"/>
+    </C:calendar-data>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`, start, end, start, end)
+
+	resp, err := c.do("REPORT", c.calendarURL(calendarID), "application/xml; charset=utf-8", []byte(body), map[string]string{"Depth": "1"})
+	if err != nil {
+		return nil, err
+	}
+
+	var result multistatus
+	if err := xml.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV response: %v", err)
+	}
+
+	var tasks []models.Task
+	for _, response := range result.Responses {
+		for _, propstat := range response.Propstats {
+			if propstat.Prop.CalendarData == "" {
+				continue
+			}
+			events, err := c.parseCalendarData(response.Href, propstat.Prop.CalendarData, timeMin.Location())
+			if err != nil {
+				fmt.Printf("⚠️ Skipping unreadable CalDAV resource %s: %v\n", response.Href, err)
+				continue
+			}
+			tasks = append(tasks, events...)
+		}
+	}
+
+	sortTasksByStart(tasks)
+	return tasks, nil
+}
+
+// GetEvent fetches a single event resource. An instance ID of a recurring
+// event resolves to the override stored for that occurrence, or else to the
+// occurrence expanded from the series.
+func (c *CalDAVBackend) GetEvent(calendarID, eventID string, loc *time.Location) (*models.Task, error) {
+	seriesID, start, _, instance := splitInstanceEventID(eventID, loc)
+	if !instance {
+		seriesID = eventID
+	}
+
+	resource, err := c.getResource(c.eventURL(calendarID, seriesID))
+	if err != nil {
+		return nil, err
+	}
+
+	if instance {
+		if override := resource.override(start, loc); override != nil {
+			task, err := vEventToTask(*override, loc)
+			if err != nil {
+				return nil, err
+			}
+			task.EventID = eventID
+			task.RecurringEventID = seriesID
+			task.Recurrence = nil
+			return &task, nil
+		}
+	}
+
+	_, event := resource.master()
+	if event == nil {
+		return nil, fmt.Errorf("event %s has no VEVENT", seriesID)
+	}
+	task, err := vEventToTask(*event, loc)
+	if err != nil {
+		return nil, err
+	}
+	task.EventID = seriesID
+
+	if instance {
+		return findOccurrence(task, eventID, start)
+	}
+	return &task, nil
+}
+
+// InsertEvent stores a new event resource named after a fresh UID
+func (c *CalDAVBackend) InsertEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	uid, err := newEventUID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := taskToICalendar(uid, task)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"If-None-Match": "*"}
+	if _, err := c.do("PUT", c.eventURL(calendarID, uid), "text/calendar; charset=utf-8", []byte(data), headers); err != nil {
+		return nil, err
+	}
+
+	task.EventID = uid
+	return &task, nil
+}
+
+// UpdateEvent patches the non-empty fields of task into the stored event,
+// keeping every property and component it doesn't change. The event is
+// written back only if nobody changed it in between; otherwise the error
+// wraps ErrEventChanged.
+func (c *CalDAVBackend) UpdateEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	return c.update(calendarID, task, loc, false)
+}
+
+// ReplaceEvent overwrites the event identified by task.EventID with task,
+// clearing what task leaves empty. Properties tasks don't hold are kept.
+func (c *CalDAVBackend) ReplaceEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	return c.update(calendarID, task, loc, true)
+}
+
+// update patches task into the stored event, first clearing the event's
+// task fields if replace is set
+func (c *CalDAVBackend) update(calendarID string, task models.Task, loc *time.Location, replace bool) (*models.Task, error) {
+	if task.EventID == "" {
+		return nil, fmt.Errorf("event ID is required for update")
+	}
+
+	seriesID, start, allDay, instance := splitInstanceEventID(task.EventID, loc)
+	if !instance {
+		seriesID = task.EventID
+	}
+
+	resource, err := c.getResource(c.eventURL(calendarID, seriesID))
+	if err != nil {
+		return nil, err
+	}
+	_, event := resource.master()
+	if event == nil {
+		return nil, fmt.Errorf("event %s has no VEVENT", seriesID)
+	}
+
+	// A change to one occurrence is stored as an override of it
+	if instance {
+		task.Recurrence = nil
+		if event, err = resource.instance(seriesID, task.EventID, start, allDay, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	if replace {
+		clearVEvent(event)
+	}
+	if err := patchVEvent(event, task); err != nil {
+		return nil, err
+	}
+	bumpSequence(event)
+
+	updated, err := vEventToTask(*event, loc)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := updated.ParseTime(); err != nil {
+		return nil, err
+	}
+
+	if err := c.putResource(resource); err != nil {
+		return nil, err
+	}
+
+	updated.EventID = task.EventID
+	if instance {
+		updated.RecurringEventID = seriesID
+		updated.Recurrence = nil
+	}
+	return &updated, nil
+}
+
+// DeleteEvent deletes an event resource by ID. An occurrence of a recurring
+// event is skipped with an EXDATE in its series instead, and any override
+// of it is removed.
+func (c *CalDAVBackend) DeleteEvent(calendarID, eventID string) error {
+	seriesID, start, allDay, instance := splitInstanceEventID(eventID, time.UTC)
+	if !instance {
+		_, err := c.do("DELETE", c.eventURL(calendarID, eventID), "", nil, nil)
+		return err
+	}
+
+	resource, err := c.getResource(c.eventURL(calendarID, seriesID))
+	if err != nil {
+		return err
+	}
+	_, event := resource.master()
+	if event == nil {
+		return fmt.Errorf("event %s has no VEVENT", seriesID)
+	}
+
+	exdate, err := parseICalLine(excludeOccurrence(nil, start, allDay)[0])
+	if err != nil {
+		return err
+	}
+	event.Properties = append(event.Properties, exdate)
+	bumpSequence(event)
+	resource.removeOverride(start, time.UTC)
+
+	return c.putResource(resource)
+}
+
+// FreeBusy is not supported: looking up other people needs a CalDAV
+// scheduling outbox, which servers rarely expose
+func (c *CalDAVBackend) FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error) {
+	return nil, fmt.Errorf("free/busy lookup is not supported by the CalDAV backend")
+}
+
+// ListCalendars lists the calendar collections in the calendar home
+func (c *CalDAVBackend) ListCalendars() ([]models.CalendarInfo, error) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:resourcetype/>
+    <D:displayname/>
+    <C:calendar-timezone/>
+  </D:prop>
+</D:propfind>`
+
+	resp, err := c.do("PROPFIND", c.homeURL, "application/xml; charset=utf-8", []byte(body), map[string]string{"Depth": "1"})
+	if err != nil {
+		return nil, err
+	}
+
+	var result multistatus
+	if err := xml.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV response: %v", err)
+	}
+
+	var calendars []models.CalendarInfo
+	for _, response := range result.Responses {
+		for _, propstat := range response.Propstats {
+			if propstat.Prop.ResourceType.Calendar == nil {
+				continue
+			}
+
+			id := resourceName(response.Href)
+			name := propstat.Prop.DisplayName
+			if name == "" {
+				name = id
+			}
+			calendars = append(calendars, models.CalendarInfo{
+				ID:         id,
+				Name:       name,
+				Primary:    id == c.defaultCalendar,
+				AccessRole: "owner",
+				TimeZone:   calendarTimeZoneID(propstat.Prop.CalendarTimeZone),
+			})
+		}
+	}
+
+	return calendars, nil
+}
+
+// calDAVResource is a calendar resource as fetched, with the ETag it had
+type calDAVResource struct {
+	url        string
+	etag       string
+	components []icalComponent
+}
+
+// getResource fetches and parses a calendar resource
+func (c *CalDAVBackend) getResource(resourceURL string) (*calDAVResource, error) {
+	data, header, err := c.request("GET", resourceURL, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := parseICalendar(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return &calDAVResource{url: resourceURL, etag: header.Get("ETag"), components: components}, nil
+}
+
+// putResource writes a fetched resource back, with a VTIMEZONE for every
+// timezone its events now use, on condition that it still has the ETag it
+// was fetched with
+func (c *CalDAVBackend) putResource(resource *calDAVResource) error {
+	var headers map[string]string
+	if resource.etag != "" {
+		headers = map[string]string{"If-Match": resource.etag}
+	}
+
+	for i := range resource.components {
+		if resource.components[i].Name == "VCALENDAR" {
+			addVTimezones(&resource.components[i])
+		}
+	}
+
+	data := formatICalendar(resource.components...)
+	_, err := c.do("PUT", resource.url, "text/calendar; charset=utf-8", []byte(data), headers)
+	return err
+}
+
+// master returns the VCALENDAR of a resource and the VEVENT defining its
+// event, the one without a RECURRENCE-ID, or nil if there is none
+func (r *calDAVResource) master() (*icalComponent, *icalComponent) {
+	for i := range r.components {
+		calendar := &r.components[i]
+		if calendar.Name != "VCALENDAR" {
+			continue
+		}
+		for j := range calendar.Components {
+			event := &calendar.Components[j]
+			if _, override := event.Get("RECURRENCE-ID"); event.Name == "VEVENT" && !override {
+				return calendar, event
+			}
+		}
+	}
+	return nil, nil
+}
+
+// override returns the VEVENT overriding the occurrence of the resource's
+// series that starts at start, or nil if it has none
+func (r *calDAVResource) override(start time.Time, loc *time.Location) *icalComponent {
+	for i := range r.components {
+		for j := range r.components[i].Components {
+			event := &r.components[i].Components[j]
+			if isOverrideOf(*event, start, loc) {
+				return event
+			}
+		}
+	}
+	return nil
+}
+
+// removeOverride removes the VEVENT overriding the occurrence that starts
+// at start, if there is one
+func (r *calDAVResource) removeOverride(start time.Time, loc *time.Location) {
+	for i := range r.components {
+		var components []icalComponent
+		for _, component := range r.components[i].Components {
+			if !isOverrideOf(component, start, loc) {
+				components = append(components, component)
+			}
+		}
+		r.components[i].Components = components
+	}
+}
+
+// instance returns the VEVENT overriding an occurrence of the resource's
+// series, adding one that copies the series if there is none yet
+func (r *calDAVResource) instance(seriesID, eventID string, start time.Time, allDay bool, loc *time.Location) (*icalComponent, error) {
+	if override := r.override(start, loc); override != nil {
+		return override, nil
+	}
+
+	calendar, master := r.master()
+	series, err := vEventToTask(*master, loc)
+	if err != nil {
+		return nil, err
+	}
+	series.EventID = seriesID
+	occurrence, err := findOccurrence(series, eventID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	override := icalComponent{
+		Name:       "VEVENT",
+		Properties: append([]icalProperty{}, master.Properties...),
+		Components: append([]icalComponent{}, master.Components...),
+	}
+	override.Remove("RRULE", "RDATE", "EXDATE")
+	override.Set(icalTimeProperty("RECURRENCE-ID", start, allDay, eventZone(master, "")))
+	if err := patchVEvent(&override, models.Task{Start: occurrence.Start, End: occurrence.End, AllDay: allDay}); err != nil {
+		return nil, err
+	}
+
+	calendar.Components = append(calendar.Components, override)
+	return &calendar.Components[len(calendar.Components)-1], nil
+}
+
+// isOverrideOf reports whether component is a VEVENT overriding the
+// occurrence that starts at start
+func isOverrideOf(component icalComponent, start time.Time, loc *time.Location) bool {
+	if component.Name != "VEVENT" {
+		return false
+	}
+	prop, ok := component.Get("RECURRENCE-ID")
+	if !ok {
+		return false
+	}
+	recurrenceID, _, err := parseICalTime(prop, loc)
+	return err == nil && recurrenceID.Equal(start)
+}
+
+// calendarURL resolves a calendar ID to its collection URL.
+// "primary" maps to the configured default calendar.
+func (c *CalDAVBackend) calendarURL(calendarID string) string {
+	if calendarID == "" || calendarID == "primary" {
+		calendarID = c.defaultCalendar
+	}
+	if strings.HasPrefix(calendarID, "http://") || strings.HasPrefix(calendarID, "https://") {
+		return strings.TrimSuffix(calendarID, "/") + "/"
+	}
+	return c.homeURL + url.PathEscape(calendarID) + "/"
+}
+
+// eventURL returns the URL of the resource holding an event
+func (c *CalDAVBackend) eventURL(calendarID, eventID string) string {
+	return c.calendarURL(calendarID) + url.PathEscape(eventID) + ".ics"
+}
+
+// parseCalendarData converts a calendar resource into tasks. The event ID is
+// the resource name, so it can be used to address the resource later;
+// occurrences of a recurring resource point back to it.
+// Floating and all-day times are placed in loc.
+func (c *CalDAVBackend) parseCalendarData(href, data string, loc *time.Location) ([]models.Task, error) {
+	vevents, err := parseVEvents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := resourceName(href)
+
+	var tasks []models.Task
+	for _, vevent := range vevents {
+		task, err := vEventToTask(vevent, loc)
+		if err != nil {
+			return nil, err
+		}
+		if eventID != "" {
+			task.EventID = eventID
+
+			// Expanded occurrences of a recurring resource carry a RECURRENCE-ID
+			if prop, ok := vevent.Get("RECURRENCE-ID"); ok {
+				if recurrenceID, allDay, err := parseICalTime(prop, loc); err == nil {
+					task.EventID = InstanceEventID(eventID, recurrenceID, allDay)
+					task.RecurringEventID = eventID
+					task.Recurrence = nil
+				}
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// do performs an authenticated CalDAV request and returns the response body
+func (c *CalDAVBackend) do(method, target, contentType string, body []byte, headers map[string]string) ([]byte, error) {
+	respBody, _, err := c.request(method, target, contentType, body, headers)
+	return respBody, err
+}
+
+// request performs an authenticated CalDAV request and returns the response
+// body and headers. A failed If-Match precondition wraps ErrEventChanged.
+func (c *CalDAVBackend) request(method, target, contentType string, body []byte, headers map[string]string) ([]byte, http.Header, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	req.Header.Set("User-Agent", "LLM-Planner-Go/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("network error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusPreconditionFailed && req.Header.Get("If-Match") != "" {
+		return nil, nil, fmt.Errorf("%w: CalDAV %s %s", ErrEventChanged, method, target)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("CalDAV %s %s returned status %d: %s", method, target, resp.StatusCode, string(respBody))
+	}
+
+	return respBody, resp.Header, nil
+}
+
+// multistatus is the WebDAV multi-status response body
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		Propstats []struct {
+			Prop struct {
+				CalendarData     string `xml:"calendar-data"`
+				DisplayName      string `xml:"displayname"`
+				CalendarTimeZone string `xml:"calendar-timezone"`
+				ResourceType     struct {
+					Calendar *struct{} `xml:"calendar"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// resourceName returns the last path segment of href without ".ics"
+func resourceName(href string) string {
+	if parsed, err := url.Parse(href); err == nil {
+		href = parsed.Path
+	}
+	name := path.Base(strings.TrimSuffix(href, "/"))
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(name, ".ics")
+}
+
+// calendarTimeZoneID extracts the TZID from a calendar-timezone VTIMEZONE
+func calendarTimeZoneID(data string) string {
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "TZID:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "TZID:"))
+		}
+	}
+	return ""
+}
+
+// newEventUID generates a random UID for a new event
+func newEventUID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate event UID: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,253 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// calDAVStandIn is a CalDAV server for tests, holding one calendar home with
+// resources kept in memory. It answers PROPFIND with its calendars, REPORT
+// with the events in a time range, recurring ones expanded, and GET, PUT and
+// DELETE with ETag preconditions.
+type calDAVStandIn struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	calendars []string
+	resources map[string]*calDAVStandInResource // path -> resource
+	nextETag  int
+
+	// changeAfterGet stands in for someone else changing a resource right
+	// after it is fetched
+	changeAfterGet bool
+	// ifMatch records the If-Match header of each PUT
+	ifMatch []string
+}
+
+type calDAVStandInResource struct {
+	data string
+	etag string
+}
+
+const calDAVStandInHome = "/dav/calendars/user/"
+
+var timeRangePattern = regexp.MustCompile(`<C:time-range start="(\w+)" end="(\w+)"/>`)
+
+func newCalDAVStandIn(t *testing.T, calendars ...string) *calDAVStandIn {
+	server := &calDAVStandIn{calendars: calendars, resources: make(map[string]*calDAVStandInResource)}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// backend returns a CalDAVBackend for the stand-in, with the first of its
+// calendars as the default
+func (s *calDAVStandIn) backend(t *testing.T) *CalDAVBackend {
+	backend, err := NewCalDAVBackend(models.CalDAVConfig{URL: s.URL + calDAVStandInHome, Calendar: s.calendars[0]}, s.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend
+}
+
+// put stores a resource directly, as another client would
+func (s *calDAVStandIn) put(calendarID, name, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(calDAVStandInHome+calendarID+"/"+name+".ics", strings.ReplaceAll(data, "\n", "\r\n"))
+}
+
+// get returns the stored data of a resource, or "" if it doesn't exist
+func (s *calDAVStandIn) get(calendarID, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resource, ok := s.resources[calDAVStandInHome+calendarID+"/"+name+".ics"]; ok {
+		return resource.data
+	}
+	return ""
+}
+
+func (s *calDAVStandIn) store(path, data string) {
+	s.nextETag++
+	s.resources[path] = &calDAVStandInResource{data: data, etag: fmt.Sprintf(`"%d"`, s.nextETag)}
+}
+
+func (s *calDAVStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	resource, exists := s.resources[r.URL.Path]
+
+	switch r.Method {
+	case "PROPFIND":
+		s.propfind(w)
+	case "REPORT":
+		s.report(w, r.URL.Path, string(body))
+	case http.MethodGet:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", resource.etag)
+		io.WriteString(w, resource.data)
+		if s.changeAfterGet {
+			s.store(r.URL.Path, resource.data)
+		}
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.ifMatch = append(s.ifMatch, r.Header.Get("If-Match"))
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && (!exists || ifMatch != resource.etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if _, err := parseICalendar(string(body)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.store(r.URL.Path, string(body))
+		w.Header().Set("ETag", s.resources[r.URL.Path].etag)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		delete(s.resources, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *calDAVStandIn) propfind(w http.ResponseWriter) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`)
+	fmt.Fprintf(&b, `<D:response><D:href>%s</D:href><D:propstat><D:prop><D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>`, calDAVStandInHome)
+	for _, calendar := range s.calendars {
+		fmt.Fprintf(&b, `<D:response><D:href>%s%s/</D:href><D:propstat><D:prop>`+
+			`<D:resourcetype><D:collection/><C:calendar/></D:resourcetype><D:displayname>%s</D:displayname>`+
+			`<C:calendar-timezone>BEGIN:VCALENDAR&#13;&#10;BEGIN:VTIMEZONE&#13;&#10;TZID:America/New_York&#13;&#10;END:VTIMEZONE&#13;&#10;END:VCALENDAR</C:calendar-timezone>`+
+			`</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>`,
+			calDAVStandInHome, calendar, calendar)
+	}
+	b.WriteString(`</D:multistatus>`)
+
+	w.WriteHeader(207)
+	io.WriteString(w, b.String())
+}
+
+// report answers a calendar-query with the events in its time range, each
+// recurring event expanded into occurrences that carry a RECURRENCE-ID, as
+// the expand element asks for
+func (s *calDAVStandIn) report(w http.ResponseWriter, collection, query string) {
+	match := timeRangePattern.FindStringSubmatch(query)
+	if match == nil {
+		http.Error(w, "missing time-range", http.StatusBadRequest)
+		return
+	}
+	timeMin, _ := time.Parse("20060102T150405Z", match[1])
+	timeMax, _ := time.Parse("20060102T150405Z", match[2])
+
+	var paths []string
+	for path := range s.resources {
+		if strings.HasPrefix(path, collection) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">`)
+	for _, path := range paths {
+		events, err := expandStandInResource(s.resources[path].data, timeMin, timeMax)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		data := formatICalendar(icalComponent{Name: "VCALENDAR", Components: events})
+		fmt.Fprintf(&b, `<D:response><D:href>%s</D:href><D:propstat><D:prop><D:getetag>%s</D:getetag><C:calendar-data>%s</C:calendar-data></D:prop></D:propstat></D:response>`,
+			path, s.resources[path].etag, strings.ReplaceAll(data, "\r", "&#13;"))
+	}
+	b.WriteString(`</D:multistatus>`)
+
+	w.WriteHeader(207)
+	io.WriteString(w, b.String())
+}
+
+// expandStandInResource returns the VEVENTs of a resource overlapping
+// [timeMin, timeMax), with recurring events expanded in UTC and overridden
+// occurrences replaced by their overrides
+func expandStandInResource(data string, timeMin, timeMax time.Time) ([]icalComponent, error) {
+	vevents, err := parseVEvents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[int64]icalComponent)
+	for _, vevent := range vevents {
+		if prop, ok := vevent.Get("RECURRENCE-ID"); ok {
+			recurrenceID, _, err := parseICalTime(prop, time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			overrides[recurrenceID.Unix()] = vevent
+		}
+	}
+
+	var events []icalComponent
+	for _, vevent := range vevents {
+		if _, ok := vevent.Get("RECURRENCE-ID"); ok {
+			continue
+		}
+		task, err := vEventToTask(vevent, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		task.EventID = ""
+
+		occurrences, err := ExpandRecurrence(task, timeMin, timeMax)
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences {
+			if !task.IsRecurring() {
+				events = append(events, vevent)
+				continue
+			}
+
+			times, _ := occurrence.ParseTime()
+			if override, ok := overrides[times.StartTime.Unix()]; ok {
+				events = append(events, override)
+				continue
+			}
+			uid, _ := vevent.Get("UID")
+			summary, _ := vevent.Get("SUMMARY")
+			events = append(events, icalComponent{Name: "VEVENT", Properties: []icalProperty{
+				uid,
+				icalTimeProperty("RECURRENCE-ID", times.StartTime, false, nil),
+				icalTimeProperty("DTSTART", times.StartTime, false, nil),
+				icalTimeProperty("DTEND", times.EndTime, false, nil),
+				summary,
+			}})
+		}
+	}
+	return events, nil
+}
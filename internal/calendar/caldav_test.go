@@ -0,0 +1,364 @@
+package calendar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// standupResource is a daily series at 9:00 New York time that crosses the
+// end of DST on 2026-11-01, with its Saturday occurrence moved to 10:00 and
+// properties the backend doesn't model
+const standupResource = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example Corp//Calendar//EN
+BEGIN:VTIMEZONE
+TZID:America/New_York
+BEGIN:DAYLIGHT
+DTSTART:20070311T020000
+RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0400
+TZNAME:EDT
+END:DAYLIGHT
+BEGIN:STANDARD
+DTSTART:20071104T020000
+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU
+TZOFFSETFROM:-0400
+TZOFFSETTO:-0500
+TZNAME:EST
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:standup@example.com
+DTSTAMP:20261001T120000Z
+DTSTART;TZID=America/New_York:20261030T090000
+DTEND;TZID=America/New_York:20261030T091500
+RRULE:FREQ=DAILY;COUNT=5
+SUMMARY:Standup
+ORGANIZER;CN="Lead, Team":mailto:lead@example.com
+CATEGORIES:WORK,DAILY
+STATUS:CONFIRMED
+SEQUENCE:2
+X-EXAMPLE-COLOR:blue
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER:-PT5M
+DESCRIPTION:Standup
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup@example.com
+RECURRENCE-ID;TZID=America/New_York:20261031T090000
+DTSTAMP:20261001T120000Z
+DTSTART;TZID=America/New_York:20261031T100000
+DTEND;TZID=America/New_York:20261031T101500
+SUMMARY:Weekend standup
+SEQUENCE:2
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestCalDAVListCalendars(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal", "work")
+
+	calendars, err := server.backend(t).ListCalendars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calendars) != 2 {
+		t.Fatalf("got %d calendars, want 2: %+v", len(calendars), calendars)
+	}
+	if calendars[0].ID != "personal" || !calendars[0].Primary || calendars[1].Primary {
+		t.Errorf("personal should be the only primary calendar: %+v", calendars)
+	}
+	if calendars[1].TimeZone != "America/New_York" {
+		t.Errorf("time zone %q, want America/New_York", calendars[1].TimeZone)
+	}
+}
+
+func TestCalDAVListEventsExpandsRecurrence(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+
+	events, err := server.backend(t).ListEvents("primary", date(2026, 10, 29), date(2026, 11, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		id, summary, start string
+	}{
+		{"standup_20261030T130000Z", "Standup", "2026-10-30T13:00:00Z"},
+		{"standup_20261031T130000Z", "Weekend standup", "2026-10-31T10:00:00-04:00"},
+		{"standup_20261101T140000Z", "Standup", "2026-11-01T14:00:00Z"},
+		{"standup_20261102T140000Z", "Standup", "2026-11-02T14:00:00Z"},
+		{"standup_20261103T140000Z", "Standup", "2026-11-03T14:00:00Z"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if event.EventID != want[i].id || event.Summary != want[i].summary || event.Start != want[i].start {
+			t.Errorf("event %d: %s %q at %s, want %s %q at %s", i, event.EventID, event.Summary, event.Start, want[i].id, want[i].summary, want[i].start)
+		}
+		if event.RecurringEventID != "standup" {
+			t.Errorf("event %d: recurring event ID %q, want standup", i, event.RecurringEventID)
+		}
+	}
+}
+
+func TestCalDAVInsertEvent(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	backend := server.backend(t)
+
+	created, err := backend.InsertEvent("primary", models.Task{
+		Summary:   "Dentist",
+		Start:     "2026-10-20T15:00:00-04:00",
+		End:       "2026-10-20T16:00:00-04:00",
+		TimeZone:  "America/New_York",
+		Reminders: []models.Reminder{{Method: models.ReminderPopup, Minutes: 30}},
+	}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := server.get("personal", created.EventID)
+	for _, line := range []string{"DTSTART;TZID=America/New_York:20261020T150000", "TZID:America/New_York", "TRIGGER:-PT30M"} {
+		if !strings.Contains(data, line+"\r\n") {
+			t.Errorf("stored event has no %q:\n%s", line, data)
+		}
+	}
+
+	got, err := backend.GetEvent("primary", created.EventID, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "Dentist" || got.Start != "2026-10-20T15:00:00-04:00" || len(got.Reminders) != 1 {
+		t.Errorf("got %+v", got)
+	}
+
+	if _, err := backend.InsertEvent("primary", models.Task{Summary: "No times"}, time.UTC); err == nil {
+		t.Error("inserting an event without times should fail")
+	}
+}
+
+func TestCalDAVUpdateEventKeepsOtherProperties(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+
+	updated, err := server.backend(t).UpdateEvent("primary", models.Task{EventID: "standup", Summary: "Daily standup"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Summary != "Daily standup" || updated.EventID != "standup" || !updated.IsRecurring() {
+		t.Errorf("got %+v", updated)
+	}
+
+	if len(server.ifMatch) != 1 || server.ifMatch[0] != `"1"` {
+		t.Errorf("PUT sent If-Match %q, want the fetched ETag", server.ifMatch)
+	}
+
+	data := server.get("personal", "standup")
+	for _, line := range []string{
+		"SUMMARY:Daily standup",
+		"SEQUENCE:3",
+		`ORGANIZER;CN="Lead, Team":mailto:lead@example.com`,
+		"CATEGORIES:WORK,DAILY",
+		"STATUS:CONFIRMED",
+		"X-EXAMPLE-COLOR:blue",
+		"RRULE:FREQ=DAILY;COUNT=5",
+		"DTSTART;TZID=America/New_York:20261030T090000",
+		"TRIGGER:-PT5M",
+		"BEGIN:VTIMEZONE",
+		"RECURRENCE-ID;TZID=America/New_York:20261031T090000",
+		"SUMMARY:Weekend standup",
+	} {
+		if !strings.Contains(data, line+"\r\n") {
+			t.Errorf("updated event has no %q:\n%s", line, data)
+		}
+	}
+	if n := strings.Count(data, "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("updated event has %d VTIMEZONEs, want 1", n)
+	}
+}
+
+func TestCalDAVUpdateEventMovesSeries(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+
+	updated, err := server.backend(t).UpdateEvent("primary", models.Task{
+		EventID: "standup",
+		Start:   "2026-10-30T14:30:00Z",
+		End:     "2026-10-30T14:45:00Z",
+	}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Start != "2026-10-30T10:30:00-04:00" {
+		t.Errorf("start %s, want 10:30 New York time", updated.Start)
+	}
+
+	data := server.get("personal", "standup")
+	if !strings.Contains(data, "DTSTART;TZID=America/New_York:20261030T103000\r\n") {
+		t.Errorf("series should keep its timezone:\n%s", data)
+	}
+}
+
+func TestCalDAVUpdateEventChangedMeanwhile(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+	server.changeAfterGet = true
+
+	_, err := server.backend(t).UpdateEvent("primary", models.Task{EventID: "standup", Summary: "Daily standup"}, time.UTC)
+	if !errors.Is(err, ErrEventChanged) {
+		t.Fatalf("got error %v, want ErrEventChanged", err)
+	}
+	if !strings.Contains(server.get("personal", "standup"), "SUMMARY:Standup\r\n") {
+		t.Error("the event changed meanwhile should not be overwritten")
+	}
+}
+
+func TestCalDAVReplaceEvent(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+
+	_, err := server.backend(t).ReplaceEvent("primary", models.Task{
+		EventID: "standup",
+		Summary: "Standup",
+		Start:   "2026-10-30T13:00:00Z",
+		End:     "2026-10-30T13:15:00Z",
+	}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := server.get("personal", "standup")
+	master := strings.SplitN(strings.SplitN(data, "BEGIN:VEVENT", 2)[1], "END:VEVENT", 2)[0]
+	for _, line := range []string{"RRULE:", "BEGIN:VALARM"} {
+		if strings.Contains(master, line) {
+			t.Errorf("replaced event still has %q:\n%s", line, data)
+		}
+	}
+	for _, line := range []string{"SUMMARY:Standup", "X-EXAMPLE-COLOR:blue", "SEQUENCE:3"} {
+		if !strings.Contains(data, line+"\r\n") {
+			t.Errorf("replaced event has no %q:\n%s", line, data)
+		}
+	}
+}
+
+func TestCalDAVDeleteEvent(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+	backend := server.backend(t)
+
+	if err := backend.DeleteEvent("primary", "standup"); err != nil {
+		t.Fatal(err)
+	}
+	if data := server.get("personal", "standup"); data != "" {
+		t.Errorf("resource still stored:\n%s", data)
+	}
+	if err := backend.DeleteEvent("primary", "standup"); err == nil {
+		t.Error("deleting a missing event should fail")
+	}
+}
+
+func TestCalDAVGetInstance(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+	backend := server.backend(t)
+
+	tests := []struct {
+		id, summary, start string
+	}{
+		{"standup_20261102T140000Z", "Standup", "2026-11-02T09:00:00-05:00"},
+		{"standup_20261031T130000Z", "Weekend standup", "2026-10-31T10:00:00-04:00"},
+	}
+	for _, test := range tests {
+		got, err := backend.GetEvent("primary", test.id, time.UTC)
+		if err != nil {
+			t.Errorf("%s: %v", test.id, err)
+			continue
+		}
+		if got.EventID != test.id || got.RecurringEventID != "standup" || got.Summary != test.summary || got.Start != test.start || got.IsRecurring() {
+			t.Errorf("%s: got %+v", test.id, got)
+		}
+	}
+
+	if _, err := backend.GetEvent("primary", "standup_20261104T140000Z", time.UTC); err == nil {
+		t.Error("an occurrence after the series ends should not be found")
+	}
+}
+
+func TestCalDAVDeleteInstance(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+	backend := server.backend(t)
+
+	for _, id := range []string{"standup_20261101T140000Z", "standup_20261031T130000Z"} {
+		if err := backend.DeleteEvent("primary", id); err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+	}
+
+	data := server.get("personal", "standup")
+	for _, line := range []string{"EXDATE:20261101T140000Z", "EXDATE:20261031T130000Z", "SEQUENCE:4"} {
+		if !strings.Contains(data, line+"\r\n") {
+			t.Errorf("series has no %q:\n%s", line, data)
+		}
+	}
+	if strings.Contains(data, "RECURRENCE-ID") {
+		t.Errorf("override of the deleted occurrence is still stored:\n%s", data)
+	}
+
+	events, err := backend.ListEvents("primary", date(2026, 10, 29), date(2026, 11, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+	if got := strings.Join(ids, " "); got != "standup_20261030T130000Z standup_20261102T140000Z standup_20261103T140000Z" {
+		t.Errorf("occurrences left: %s", got)
+	}
+}
+
+func TestCalDAVUpdateInstance(t *testing.T) {
+	server := newCalDAVStandIn(t, "personal")
+	server.put("personal", "standup", standupResource)
+	backend := server.backend(t)
+
+	updated, err := backend.UpdateEvent("primary", models.Task{EventID: "standup_20261102T140000Z", Summary: "Planning standup"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.EventID != "standup_20261102T140000Z" || updated.RecurringEventID != "standup" || updated.Start != "2026-11-02T09:00:00-05:00" {
+		t.Errorf("got %+v", updated)
+	}
+
+	data := server.get("personal", "standup")
+	for _, line := range []string{"RECURRENCE-ID;TZID=America/New_York:20261102T090000", "SUMMARY:Planning standup", "SUMMARY:Standup", "RRULE:FREQ=DAILY;COUNT=5"} {
+		if !strings.Contains(data, line+"\r\n") {
+			t.Errorf("series has no %q:\n%s", line, data)
+		}
+	}
+	if n := strings.Count(data, "RRULE:FREQ=DAILY"); n != 1 {
+		t.Errorf("the override should not repeat: %d RRULEs", n)
+	}
+
+	got, err := backend.GetEvent("primary", "standup_20261102T140000Z", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Summary != "Planning standup" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
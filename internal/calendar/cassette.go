@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/cassette"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// CassetteBackend records every call made to a backend into a cassette or,
+// when the cassette is replaying, serves the recorded results back without
+// touching any calendar
+type CassetteBackend struct {
+	backend  CalendarBackend // nil when replaying
+	cassette *cassette.Cassette
+}
+
+// NewCassetteBackend wraps backend for recording, or replays the cassette
+// when it is in replay mode, in which case backend may be nil
+func NewCassetteBackend(backend CalendarBackend, c *cassette.Cassette) *CassetteBackend {
+	return &CassetteBackend{backend: backend, cassette: c}
+}
+
+// GetName returns the backend name
+func (b *CassetteBackend) GetName() string {
+	if b.cassette.Replaying() {
+		return fmt.Sprintf("Cassette replay (%s)", b.cassette.Path())
+	}
+	return fmt.Sprintf("%s (recording to %s)", b.backend.GetName(), b.cassette.Path())
+}
+
+// ListEvents returns events overlapping [timeMin, timeMax)
+func (b *CassetteBackend) ListEvents(calendarID string, timeMin, timeMax time.Time) ([]models.Task, error) {
+	request := map[string]interface{}{"calendar_id": calendarID, "time_min": timeMin, "time_max": timeMax}
+	var tasks []models.Task
+	err := b.call("ListEvents", request, &tasks, func() (interface{}, error) {
+		var err error
+		tasks, err = b.backend.ListEvents(calendarID, timeMin, timeMax)
+		return tasks, err
+	})
+	return tasks, err
+}
+
+// GetEvent returns a single event by ID
+func (b *CassetteBackend) GetEvent(calendarID, eventID string, loc *time.Location) (*models.Task, error) {
+	request := map[string]interface{}{"calendar_id": calendarID, "event_id": eventID}
+	var task *models.Task
+	err := b.call("GetEvent", request, &task, func() (interface{}, error) {
+		var err error
+		task, err = b.backend.GetEvent(calendarID, eventID, loc)
+		return task, err
+	})
+	return task, err
+}
+
+// InsertEvent creates an event and returns it with its EventID set
+func (b *CassetteBackend) InsertEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	request := map[string]interface{}{"calendar_id": calendarID, "event": task}
+	var created *models.Task
+	err := b.call("InsertEvent", request, &created, func() (interface{}, error) {
+		var err error
+		created, err = b.backend.InsertEvent(calendarID, task, loc)
+		return created, err
+	})
+	return created, err
+}
+
+// UpdateEvent updates the event identified by task.EventID
+func (b *CassetteBackend) UpdateEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	request := map[string]interface{}{"calendar_id": calendarID, "event": task}
+	var updated *models.Task
+	err := b.call("UpdateEvent", request, &updated, func() (interface{}, error) {
+		var err error
+		updated, err = b.backend.UpdateEvent(calendarID, task, loc)
+		return updated, err
+	})
+	return updated, err
+}
+
+// ReplaceEvent overwrites the event identified by task.EventID with task
+func (b *CassetteBackend) ReplaceEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	request := map[string]interface{}{"calendar_id": calendarID, "event": task}
+	var replaced *models.Task
+	err := b.call("ReplaceEvent", request, &replaced, func() (interface{}, error) {
+		var err error
+		replaced, err = b.backend.ReplaceEvent(calendarID, task, loc)
+		return replaced, err
+	})
+	return replaced, err
+}
+
+// DeleteEvent deletes an event by ID
+func (b *CassetteBackend) DeleteEvent(calendarID, eventID string) error {
+	request := map[string]interface{}{"calendar_id": calendarID, "event_id": eventID}
+	return b.call("DeleteEvent", request, nil, func() (interface{}, error) {
+		return nil, b.backend.DeleteEvent(calendarID, eventID)
+	})
+}
+
+// ListCalendars returns the calendars the user has access to
+func (b *CassetteBackend) ListCalendars() ([]models.CalendarInfo, error) {
+	var calendars []models.CalendarInfo
+	err := b.call("ListCalendars", map[string]interface{}{}, &calendars, func() (interface{}, error) {
+		var err error
+		calendars, err = b.backend.ListCalendars()
+		return calendars, err
+	})
+	return calendars, err
+}
+
+// FreeBusy returns the busy times of each calendar or person it could look up
+func (b *CassetteBackend) FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error) {
+	request := map[string]interface{}{"ids": ids, "time_min": timeMin, "time_max": timeMax}
+	var busy map[string][]models.TimeSlot
+	err := b.call("FreeBusy", request, &busy, func() (interface{}, error) {
+		var err error
+		busy, err = b.backend.FreeBusy(ids, timeMin, timeMax)
+		return busy, err
+	})
+	return busy, err
+}
+
+// call replays the next recorded result of method into response, or runs
+// the real call and records it. Calls are replayed in recorded order rather
+// than matched on their arguments, since those contain the current time.
+func (b *CassetteBackend) call(method string, request, response interface{}, run func() (interface{}, error)) error {
+	if b.cassette.Replaying() {
+		interaction, err := b.cassette.Next(cassette.KindCalendar, method)
+		if err != nil {
+			return err
+		}
+		return interaction.Decode(response)
+	}
+
+	result, err := run()
+	if recordErr := b.cassette.Record(cassette.KindCalendar, method, request, result, err); recordErr != nil {
+		fmt.Printf("⚠️ Failed to record %s: %v\n", method, recordErr)
+	}
+	return err
+}
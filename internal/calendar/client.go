@@ -1,20 +1,116 @@
 package calendar
 
 import (
-	"google.golang.org/api/calendar/v3"
+	"sort"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 )
 
-// Client wraps the Google Calendar service
+// DefaultCalendarID is the calendar used when none is configured
+const DefaultCalendarID = "primary"
+
+// Client wraps a calendar backend, the calendar it reads from and writes to,
+// and the timezone used for day boundaries and event times. Changes are
+// recorded in the journal when one is set.
 type Client struct {
-	service *calendar.Service
+	backend     CalendarBackend
+	calendarID  string
+	location    *time.Location
+	journal     *Journal
+	sendUpdates string // how attendees hear about changes unless a change says otherwise
+}
+
+// NewClient creates a new calendar client for the given calendar in the local timezone
+func NewClient(backend CalendarBackend, calendarID string) *Client {
+	if calendarID == "" {
+		calendarID = DefaultCalendarID
+	}
+	return &Client{backend: backend, calendarID: calendarID, location: time.Local}
+}
+
+// GetBackend returns the underlying calendar backend
+func (c *Client) GetBackend() CalendarBackend {
+	return c.backend
+}
+
+// GetCalendarID returns the calendar this client reads from and writes to
+func (c *Client) GetCalendarID() string {
+	return c.calendarID
+}
+
+// WithCalendar returns a client for another calendar on the same backend.
+// An empty calendarID returns the client unchanged.
+func (c *Client) WithCalendar(calendarID string) *Client {
+	if calendarID == "" || calendarID == c.calendarID {
+		return c
+	}
+
+	client := *c
+	client.calendarID = calendarID
+	return &client
+}
+
+// WithLocation returns a client that works in the given timezone
+func (c *Client) WithLocation(location *time.Location) *Client {
+	if location == nil || location == c.location {
+		return c
+	}
+
+	client := *c
+	client.location = location
+	return &client
+}
+
+// WithJournal returns a client that records its changes in journal
+func (c *Client) WithJournal(journal *Journal) *Client {
+	client := *c
+	client.journal = journal
+	return &client
+}
+
+// WithSendUpdates returns a client that tells attendees about new and
+// changed events in the given way, e.g. "all", unless a change says otherwise
+func (c *Client) WithSendUpdates(sendUpdates string) *Client {
+	client := *c
+	client.sendUpdates = sendUpdates
+	return &client
+}
+
+// GetJournal returns the journal changes are recorded in, or nil
+func (c *Client) GetJournal() *Journal {
+	return c.journal
+}
+
+// Location returns the timezone the client works in
+func (c *Client) Location() *time.Location {
+	return c.location
+}
+
+// Now returns the current time in the client's timezone
+func (c *Client) Now() time.Time {
+	return time.Now().In(c.location)
+}
+
+// ListCalendars returns the calendars available on the backend
+func (c *Client) ListCalendars() ([]models.CalendarInfo, error) {
+	return c.backend.ListCalendars()
 }
 
-// NewClient creates a new calendar client
-func NewClient(service *calendar.Service) *Client {
-	return &Client{service: service}
+// FreeBusy returns the busy times of calendars or people, by email, that
+// the backend could look up
+func (c *Client) FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error) {
+	return c.backend.FreeBusy(ids, timeMin, timeMax)
 }
 
-// GetService returns the underlying calendar service
-func (c *Client) GetService() *calendar.Service {
-	return c.service
+// sortTasksByStart orders tasks by their parsed start time
+func sortTasksByStart(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		startI, errI := time.Parse(time.RFC3339, tasks[i].Start)
+		startJ, errJ := time.Parse(time.RFC3339, tasks[j].Start)
+		if errI != nil || errJ != nil {
+			return tasks[i].Start < tasks[j].Start
+		}
+		return startI.Before(startJ)
+	})
 }
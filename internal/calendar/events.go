@@ -5,102 +5,200 @@ import (
 	"time"
 
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
-	"google.golang.org/api/calendar/v3"
 )
 
-// GetTodaysEvents retrieves today's events
-// GetTodaysEvents retrieves today's events
 // GetTodaysEvents retrieves today's events
 func (c *Client) GetTodaysEvents() ([]models.Task, error) {
-	now := time.Now()
+	now := c.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	events, err := c.service.Events.List("primary").
-		TimeMin(startOfDay.Format(time.RFC3339)).
-		TimeMax(endOfDay.Format(time.RFC3339)).
-		SingleEvents(true).
-		OrderBy("startTime").
-		Do()
-
+	tasks, err := c.ListEvents(startOfDay, endOfDay)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve events: %v", err)
 	}
 
-	var tasks []models.Task
-	for _, event := range events.Items { // Remove unused 'i' variable
-		start := event.Start.DateTime
-		if start == "" {
-			start = event.Start.Date + "T00:00:00+05:30"
-		}
-		end := event.End.DateTime
-		if end == "" {
-			end = event.End.Date + "T23:59:59+05:30"
-		}
-		
-		task := models.Task{
-			Summary: event.Summary,
-			Start:   start,
-			End:     end,
-		}
-		tasks = append(tasks, task)
+	return tasks, nil
+}
+
+// ListEvents lists events in the client's calendar, tagged with the calendar ID
+// and with start and end times expressed in the client's timezone
+func (c *Client) ListEvents(timeMin, timeMax time.Time) ([]models.Task, error) {
+	tasks, err := c.backend.ListEvents(c.calendarID, timeMin.In(c.location), timeMax.In(c.location))
+	if err != nil {
+		return nil, err
 	}
 
+	for i := range tasks {
+		tasks[i].CalendarID = c.calendarID
+		tasks[i].Start = c.inLocation(tasks[i].Start)
+		tasks[i].End = c.inLocation(tasks[i].End)
+	}
 	return tasks, nil
 }
 
+// inLocation re-expresses an RFC3339 time in the client's timezone
+func (c *Client) inLocation(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.In(c.location).Format(time.RFC3339)
+}
+
+// timeZoneName returns the IANA name of the client's timezone, or "" when
+// only the process-local zone is known
+func (c *Client) timeZoneName() string {
+	name := c.location.String()
+	if name == "Local" {
+		return ""
+	}
+	return name
+}
 
 // CreateEvent creates a new calendar event
 func (c *Client) CreateEvent(task models.Task) error {
 	fmt.Printf("🔍 Creating event: %s\n", task.Summary)
 	fmt.Printf("  Start: %s\n", task.Start)
 	fmt.Printf("  End: %s\n", task.End)
-	
-	event := &calendar.Event{
-		Summary: task.Summary,
-		Start: &calendar.EventDateTime{
-			DateTime: task.Start,
-			TimeZone: "Asia/Kolkata",
-		},
-		End: &calendar.EventDateTime{
-			DateTime: task.End,
-			TimeZone: "Asia/Kolkata",
-		},
-	}
-	
-	   createdEvent, err := c.service.Events.Insert("pikobyte006@gmail.com", event).Do()
-	   if err != nil {
-			   return fmt.Errorf("failed to create event: %v", err)
-	   }
-	   // Set the EventID on the task (if pointer, else return it)
-	   task.EventID = createdEvent.Id
-	   fmt.Printf("✅ Event created successfully with ID: %s\n", createdEvent.Id)
-	   return nil
+
+	if task.TimeZone == "" {
+		task.TimeZone = c.timeZoneName()
+	}
+	if task.SendUpdates == "" && len(task.Attendees) > 0 {
+		task.SendUpdates = c.sendUpdates
+	}
+
+	createdEvent, err := c.backend.InsertEvent(c.calendarID, task, c.location)
+	if err != nil {
+		return fmt.Errorf("failed to create event: %v", err)
+	}
+	c.record(OperationCreate, *createdEvent, nil)
+	fmt.Printf("✅ Event created successfully with ID: %s\n", createdEvent.EventID)
+	return nil
 }
 
 // CreateMultipleEvents creates multiple events at once
 func (c *Client) CreateMultipleEvents(tasks []models.Task) (int, error) {
+	return c.CreateEventsWithProgress(tasks, nil)
+}
+
+// CreateEventsWithProgress creates events one at a time, calling created,
+// if set, after each one that succeeds
+func (c *Client) CreateEventsWithProgress(tasks []models.Task, created func(models.Task)) (int, error) {
 	successCount := 0
 	for _, task := range tasks {
 		if err := c.CreateEvent(task); err != nil {
 			fmt.Printf("⚠️ Failed to create event '%s': %v\n", task.Summary, err)
 		} else {
 			successCount++
+			if created != nil {
+				created(task)
+			}
 		}
 	}
 	return successCount, nil
 }
 
+// GetEvent retrieves a single event by ID
+func (c *Client) GetEvent(eventID string) (*models.Task, error) {
+	task, err := c.backend.GetEvent(c.calendarID, eventID, c.location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve event: %v", err)
+	}
+
+	task.CalendarID = c.calendarID
+	task.Start = c.inLocation(task.Start)
+	task.End = c.inLocation(task.End)
+	return task, nil
+}
+
+// UpdateEvent updates the event identified by task.EventID. Empty fields keep
+// their current values.
+func (c *Client) UpdateEvent(task models.Task) (*models.Task, error) {
+	if task.EventID == "" {
+		return nil, fmt.Errorf("event ID is required for update")
+	}
+	if task.TimeZone == "" && (task.Start != "" || task.End != "") {
+		task.TimeZone = c.timeZoneName()
+	}
+	if task.SendUpdates == "" {
+		task.SendUpdates = c.sendUpdates
+	}
+
+	previous := c.snapshot(task.EventID)
+
+	updatedEvent, err := c.backend.UpdateEvent(c.calendarID, task, c.location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+	c.record(OperationUpdate, *updatedEvent, previous)
+
+	updatedEvent.CalendarID = c.calendarID
+	updatedEvent.Start = c.inLocation(updatedEvent.Start)
+	updatedEvent.End = c.inLocation(updatedEvent.End)
+	fmt.Printf("✏️ Event updated successfully: %s\n", updatedEvent.EventID)
+	return updatedEvent, nil
+}
+
 // DeleteEvent deletes an event by ID
 func (c *Client) DeleteEvent(eventID string) error {
-	   err := c.service.Events.Delete("pikobyte006@gmail.com", eventID).Do()
+	previous := c.snapshot(eventID)
+
+	err := c.backend.DeleteEvent(c.calendarID, eventID)
 	if err != nil {
 		return fmt.Errorf("failed to delete event: %v", err)
 	}
+	if previous == nil {
+		previous = &models.Task{EventID: eventID}
+	}
+	c.record(OperationDelete, *previous, nil)
 	fmt.Printf("🗑️ Event deleted successfully: %s\n", eventID)
 	return nil
 }
 
+// snapshot fetches the current version of an event for the journal. It
+// returns nil when nothing is recorded or the event cannot be read.
+func (c *Client) snapshot(eventID string) *models.Task {
+	if c.journal == nil {
+		return nil
+	}
+
+	task, err := c.backend.GetEvent(c.calendarID, eventID, c.location)
+	if err != nil {
+		fmt.Printf("⚠️ Could not read event %s for the undo journal: %v\n", eventID, err)
+		return nil
+	}
+	return task
+}
+
+// record adds a change to the journal, if the client has one
+func (c *Client) record(operation string, event models.Task, previous *models.Task) {
+	c.recordAll(c.entry(operation, event, previous))
+}
+
+// entry returns a journal entry for a change to the client's calendar
+func (c *Client) entry(operation string, event models.Task, previous *models.Task) JournalEntry {
+	return JournalEntry{
+		Operation:  operation,
+		CalendarID: c.calendarID,
+		Event:      event,
+		Previous:   previous,
+	}
+}
+
+// recordAll adds the entries of one change to the journal, if the client
+// has one, so they are undone together
+func (c *Client) recordAll(entries ...JournalEntry) {
+	if c.journal == nil {
+		return
+	}
+
+	if err := c.journal.Record(entries...); err != nil {
+		fmt.Printf("⚠️ Failed to record %s in the undo journal: %v\n", entries[len(entries)-1].Operation, err)
+	}
+}
+
 // DeleteEventBySummaryAndTime deletes an event by summary and time (first match)
 func (c *Client) DeleteEventBySummaryAndTime(summary, start, end string) error {
 	// Search for events in a reasonable window (e.g., +/- 1 day)
@@ -112,24 +210,26 @@ func (c *Client) DeleteEventBySummaryAndTime(summary, start, end string) error {
 	windowStart := startTime.Add(-24 * time.Hour)
 	windowEnd := endTime.Add(24 * time.Hour)
 
-	events, err := c.service.Events.List("primary").
-		TimeMin(windowStart.Format(time.RFC3339)).
-		TimeMax(windowEnd.Format(time.RFC3339)).
-		SingleEvents(true).
-		OrderBy("startTime").
-		Do()
+	events, err := c.ListEvents(windowStart, windowEnd)
 	if err != nil {
 		return fmt.Errorf("unable to search for events: %v", err)
 	}
 
-	for _, event := range events.Items {
-		if event.Summary == summary &&
-			event.Start != nil && event.End != nil &&
-			(event.Start.DateTime == start || event.Start.Date == start[:10]) &&
-			(event.End.DateTime == end || event.End.Date == end[:10]) {
+	for _, event := range events {
+		if event.Summary == summary && matchesEventTime(event, start, end) {
 			// Found a match, delete it
-			return c.DeleteEvent(event.Id)
+			return c.DeleteEvent(event.EventID)
 		}
 	}
 	return fmt.Errorf("no matching event found to delete")
 }
+
+// matchesEventTime checks whether an event starts and ends at the given times.
+// All-day events only need to match on the date.
+func matchesEventTime(event models.Task, start, end string) bool {
+	if event.AllDay {
+		return len(event.Start) >= 10 && len(event.End) >= 10 &&
+			event.Start[:10] == start[:10] && event.End[:10] == end[:10]
+	}
+	return event.Start == start && event.End == end
+}
@@ -0,0 +1,390 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+	"google.golang.org/api/calendar/v3"
+)
+
+// GoogleBackend implements CalendarBackend on top of the Google Calendar API
+type GoogleBackend struct {
+	service *calendar.Service
+}
+
+// NewGoogleBackend creates a new Google Calendar backend
+func NewGoogleBackend(service *calendar.Service) *GoogleBackend {
+	return &GoogleBackend{service: service}
+}
+
+// GetService returns the underlying calendar service
+func (g *GoogleBackend) GetService() *calendar.Service {
+	return g.service
+}
+
+// GetName returns the backend name
+func (g *GoogleBackend) GetName() string {
+	return "Google Calendar"
+}
+
+// ListEvents lists single events between timeMin and timeMax
+func (g *GoogleBackend) ListEvents(calendarID string, timeMin, timeMax time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+
+	err := g.service.Events.List(calendarID).
+		TimeMin(timeMin.Format(time.RFC3339)).
+		TimeMax(timeMax.Format(time.RFC3339)).
+		SingleEvents(true).
+		OrderBy("startTime").
+		Pages(nil, func(events *calendar.Events) error {
+			for _, event := range events.Items {
+				tasks = append(tasks, eventToTask(event, timeMin.Location()))
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetEvent returns a single event by ID
+func (g *GoogleBackend) GetEvent(calendarID, eventID string, loc *time.Location) (*models.Task, error) {
+	event, err := g.service.Events.Get(calendarID, eventID).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	task := eventToTask(event, loc)
+	return &task, nil
+}
+
+// InsertEvent creates a new event, inviting its attendees
+func (g *GoogleBackend) InsertEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	event := taskToEvent(task)
+	call := g.service.Events.Insert(calendarID, event)
+	if task.SendUpdates != "" {
+		call = call.SendUpdates(task.SendUpdates)
+	}
+	if event.ConferenceData != nil {
+		call = call.ConferenceDataVersion(1)
+	}
+
+	created, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	result := eventToTask(created, loc)
+	return &result, nil
+}
+
+// UpdateEvent patches the event identified by task.EventID
+func (g *GoogleBackend) UpdateEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	if task.EventID == "" {
+		return nil, fmt.Errorf("event ID is required for update")
+	}
+
+	event := taskToEvent(task)
+	if len(event.Attendees) > 0 {
+		// The attendees replace the event's, which include the user's own
+		// entry and rooms that tasks leave out
+		current, err := g.service.Events.Get(calendarID, task.EventID).Do()
+		if err != nil {
+			return nil, err
+		}
+		keepOwnAttendees(event, current)
+	}
+	call := g.service.Events.Patch(calendarID, task.EventID, event)
+	if task.SendUpdates != "" {
+		call = call.SendUpdates(task.SendUpdates)
+	}
+	if event.ConferenceData != nil {
+		call = call.ConferenceDataVersion(1)
+	}
+
+	updated, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	result := eventToTask(updated, loc)
+	return &result, nil
+}
+
+// ReplaceEvent overwrites the fields of the event identified by
+// task.EventID that tasks hold, clearing those task leaves empty. The
+// user's own entry, rooms and an existing video call are kept.
+func (g *GoogleBackend) ReplaceEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	if task.EventID == "" {
+		return nil, fmt.Errorf("event ID is required for update")
+	}
+
+	current, err := g.service.Events.Get(calendarID, task.EventID).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := taskToEvent(task)
+	keepOwnAttendees(replacement, current)
+	if task.Conference != nil && replacement.ConferenceData == nil {
+		replacement.ConferenceData = current.ConferenceData
+	}
+	if replacement.Reminders == nil {
+		replacement.Reminders = &calendar.EventReminders{UseDefault: true}
+	}
+
+	// Fields tasks don't hold, such as colors and visibility, are kept
+	event := *current
+	event.Summary = replacement.Summary
+	event.Description = replacement.Description
+	event.Location = replacement.Location
+	event.Recurrence = replacement.Recurrence
+	event.Attendees = replacement.Attendees
+	event.ConferenceData = replacement.ConferenceData
+	event.Reminders = replacement.Reminders
+	event.Start = replacement.Start
+	event.End = replacement.End
+
+	call := g.service.Events.Update(calendarID, task.EventID, &event).ConferenceDataVersion(1)
+	if task.SendUpdates != "" {
+		call = call.SendUpdates(task.SendUpdates)
+	}
+
+	replaced, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	result := eventToTask(replaced, loc)
+	return &result, nil
+}
+
+// keepOwnAttendees adds the user's own entry and rooms of the stored event,
+// which tasks leave out, to the attendees written back
+func keepOwnAttendees(event, current *calendar.Event) {
+	for _, attendee := range current.Attendees {
+		if attendee.Self || attendee.Resource {
+			event.Attendees = append(event.Attendees, attendee)
+		}
+	}
+}
+
+// DeleteEvent deletes an event by ID
+func (g *GoogleBackend) DeleteEvent(calendarID, eventID string) error {
+	return g.service.Events.Delete(calendarID, eventID).Do()
+}
+
+// ListCalendars returns the calendars in the user's calendar list
+func (g *GoogleBackend) ListCalendars() ([]models.CalendarInfo, error) {
+	var calendars []models.CalendarInfo
+
+	err := g.service.CalendarList.List().Pages(nil, func(list *calendar.CalendarList) error {
+		for _, entry := range list.Items {
+			name := entry.SummaryOverride
+			if name == "" {
+				name = entry.Summary
+			}
+			calendars = append(calendars, models.CalendarInfo{
+				ID:         entry.Id,
+				Name:       name,
+				Primary:    entry.Primary,
+				AccessRole: entry.AccessRole,
+				TimeZone:   entry.TimeZone,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// FreeBusy asks Google for the busy times of calendars and people. Those
+// Google reports errors for, such as people outside the user's domain who
+// don't share their calendar, are left out.
+func (g *GoogleBackend) FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error) {
+	request := &calendar.FreeBusyRequest{
+		TimeMin: timeMin.Format(time.RFC3339),
+		TimeMax: timeMax.Format(time.RFC3339),
+	}
+	for _, id := range ids {
+		request.Items = append(request.Items, &calendar.FreeBusyRequestItem{Id: id})
+	}
+
+	response, err := g.service.Freebusy.Query(request).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make(map[string][]models.TimeSlot)
+	for id, cal := range response.Calendars {
+		if len(cal.Errors) > 0 {
+			fmt.Printf("⚠️ No free/busy for %s: %s\n", id, cal.Errors[0].Reason)
+			continue
+		}
+
+		slots := []models.TimeSlot{}
+		for _, period := range cal.Busy {
+			start, err := time.Parse(time.RFC3339, period.Start)
+			if err != nil {
+				continue
+			}
+			end, err := time.Parse(time.RFC3339, period.End)
+			if err != nil {
+				continue
+			}
+			slots = append(slots, models.TimeSlot{Start: start.In(timeMin.Location()), End: end.In(timeMin.Location())})
+		}
+		busy[id] = slots
+	}
+	return busy, nil
+}
+
+// eventToTask converts a Google Calendar event into a task. All-day events,
+// which carry only a date, are placed in loc.
+func eventToTask(event *calendar.Event, loc *time.Location) models.Task {
+	task := models.Task{
+		Summary:     event.Summary,
+		EventID:     event.Id,
+		Description: event.Description,
+		Location:    event.Location,
+		Recurrence:  event.Recurrence,
+	}
+	task.RecurringEventID = event.RecurringEventId
+
+	// The user's own entry and rooms aren't attendees
+	for _, attendee := range event.Attendees {
+		if attendee.Self || attendee.Resource {
+			continue
+		}
+		task.Attendees = append(task.Attendees, models.Attendee{
+			Email:          attendee.Email,
+			Name:           attendee.DisplayName,
+			ResponseStatus: attendee.ResponseStatus,
+		})
+	}
+	if link := conferenceLink(event); link != "" {
+		task.Conference = &models.Conference{Link: link}
+	}
+	if event.Reminders != nil && !event.Reminders.UseDefault {
+		for _, reminder := range event.Reminders.Overrides {
+			task.Reminders = append(task.Reminders, models.Reminder{Method: reminder.Method, Minutes: int(reminder.Minutes)})
+		}
+	}
+
+	if event.Start != nil {
+		task.Start = event.Start.DateTime
+		task.TimeZone = event.Start.TimeZone
+		if task.Start == "" {
+			if date, err := time.ParseInLocation("2006-01-02", event.Start.Date, loc); err == nil {
+				task.Start = date.Format(time.RFC3339)
+			}
+			task.AllDay = true
+		}
+	}
+	if event.End != nil {
+		task.End = event.End.DateTime
+		if task.End == "" {
+			// The end date of an all-day event is exclusive
+			if date, err := time.ParseInLocation("2006-01-02", event.End.Date, loc); err == nil {
+				task.End = date.Add(-time.Second).Format(time.RFC3339)
+			}
+		}
+	}
+
+	return task
+}
+
+// taskToEvent converts a task into a Google Calendar event. Attendees
+// without an email can't be invited and are left out. Reminders replace
+// the calendar's defaults.
+func taskToEvent(task models.Task) *calendar.Event {
+	event := &calendar.Event{
+		Summary:     task.Summary,
+		Description: task.Description,
+		Location:    task.Location,
+		Recurrence:  task.Recurrence,
+	}
+
+	for _, attendee := range task.Attendees {
+		if attendee.Email == "" {
+			fmt.Printf("⚠️ Not inviting %s to %s: no email\n", attendee.Name, task.Summary)
+			continue
+		}
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{
+			Email:          attendee.Email,
+			DisplayName:    attendee.Name,
+			ResponseStatus: attendee.ResponseStatus,
+		})
+	}
+	if len(task.Reminders) > 0 {
+		// UseDefault and a zero Minutes have to be sent even though they're zero values
+		event.Reminders = &calendar.EventReminders{ForceSendFields: []string{"UseDefault"}}
+		for _, reminder := range task.Reminders {
+			event.Reminders.Overrides = append(event.Reminders.Overrides, &calendar.EventReminder{
+				Method:          reminder.Method,
+				Minutes:         int64(reminder.Minutes),
+				ForceSendFields: []string{"Minutes"},
+			})
+		}
+	}
+	if task.Conference != nil && task.Conference.Create {
+		event.ConferenceData = &calendar.ConferenceData{
+			CreateRequest: &calendar.CreateConferenceRequest{
+				RequestId:             fmt.Sprintf("planner-%d", time.Now().UnixNano()),
+				ConferenceSolutionKey: &calendar.ConferenceSolutionKey{Type: "hangoutsMeet"},
+			},
+		}
+	}
+
+	// Leave unset times out so a patch does not clear them
+	if task.Start != "" {
+		event.Start = &calendar.EventDateTime{DateTime: task.Start, TimeZone: task.TimeZone}
+		if task.AllDay {
+			event.Start = &calendar.EventDateTime{Date: allDayDate(task.Start, false)}
+		}
+	}
+	if task.End != "" {
+		event.End = &calendar.EventDateTime{DateTime: task.End, TimeZone: task.TimeZone}
+		if task.AllDay {
+			// The end date of an all-day event is exclusive, while a task ends
+			// either at the last second of its day or at the next midnight
+			event.End = &calendar.EventDateTime{Date: allDayDate(task.End, true)}
+		}
+	}
+
+	return event
+}
+
+// allDayDate returns the date of an all-day event's start, or the exclusive
+// date of its end. Times that don't parse keep their date part.
+func allDayDate(value string, end bool) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if len(value) >= 10 {
+			return value[:10]
+		}
+		return value
+	}
+	if end {
+		t = t.Add(-time.Second).AddDate(0, 0, 1)
+	}
+	return t.Format("2006-01-02")
+}
+
+// conferenceLink returns the join link of an event's video call, if any
+func conferenceLink(event *calendar.Event) string {
+	if event.ConferenceData != nil {
+		for _, entry := range event.ConferenceData.EntryPoints {
+			if entry.EntryPointType == "video" {
+				return entry.Uri
+			}
+		}
+	}
+	return event.HangoutLink
+}
@@ -0,0 +1,753 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// icalProperty is a single content line of an iCalendar object
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icalComponent is a parsed component such as VEVENT
+type icalComponent struct {
+	Name       string
+	Properties []icalProperty
+	Components []icalComponent // directly nested components such as VALARM
+}
+
+// Get returns the first property with the given name
+func (c *icalComponent) Get(name string) (icalProperty, bool) {
+	for _, prop := range c.Properties {
+		if prop.Name == name {
+			return prop, true
+		}
+	}
+	return icalProperty{}, false
+}
+
+// Set replaces the properties named prop.Name with prop, where the first
+// of them was, or adds it
+func (c *icalComponent) Set(prop icalProperty) {
+	var properties []icalProperty
+	replaced := false
+	for _, existing := range c.Properties {
+		if existing.Name != prop.Name {
+			properties = append(properties, existing)
+		} else if !replaced {
+			properties = append(properties, prop)
+			replaced = true
+		}
+	}
+	if !replaced {
+		properties = append(properties, prop)
+	}
+	c.Properties = properties
+}
+
+// Remove removes every property with one of the given names
+func (c *icalComponent) Remove(names ...string) {
+	var properties []icalProperty
+	for _, prop := range c.Properties {
+		keep := true
+		for _, name := range names {
+			if prop.Name == name {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			properties = append(properties, prop)
+		}
+	}
+	c.Properties = properties
+}
+
+// parseICalendar parses iCalendar data into its top-level components,
+// normally a single VCALENDAR, keeping every property and nested component
+// so it can be written back unchanged
+func parseICalendar(data string) ([]icalComponent, error) {
+	// Unfold long lines (RFC 5545 section 3.1)
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var components []icalComponent
+	var open []*icalComponent
+
+	for _, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			open = append(open, &icalComponent{Name: strings.ToUpper(prop.Value)})
+		case "END":
+			if len(open) == 0 || !strings.EqualFold(open[len(open)-1].Name, prop.Value) {
+				return nil, fmt.Errorf("unexpected END:%s in iCalendar data", prop.Value)
+			}
+			done := *open[len(open)-1]
+			open = open[:len(open)-1]
+			if len(open) == 0 {
+				components = append(components, done)
+			} else {
+				parent := open[len(open)-1]
+				parent.Components = append(parent.Components, done)
+			}
+		default:
+			if len(open) > 0 {
+				current := open[len(open)-1]
+				current.Properties = append(current.Properties, prop)
+			}
+		}
+	}
+	if len(open) > 0 {
+		return nil, fmt.Errorf("iCalendar data ends inside %s", open[len(open)-1].Name)
+	}
+
+	return components, nil
+}
+
+// parseVEvents extracts all VEVENT components from iCalendar data
+func parseVEvents(data string) ([]icalComponent, error) {
+	components, err := parseICalendar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icalComponent
+	for _, component := range components {
+		if component.Name == "VEVENT" {
+			events = append(events, component)
+		}
+		for _, nested := range component.Components {
+			if nested.Name == "VEVENT" {
+				events = append(events, nested)
+			}
+		}
+	}
+	return events, nil
+}
+
+// parseICalLine parses a single unfolded content line
+func parseICalLine(line string) (icalProperty, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return icalProperty{}, fmt.Errorf("invalid iCalendar line: %q", line)
+	}
+
+	// Split the name and parameters on semicolons outside quoted values
+	var head []string
+	from := 0
+	inQuotes = false
+	for i, r := range line[:colon] {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ';' && !inQuotes {
+			head = append(head, line[from:i])
+			from = i + 1
+		}
+	}
+	head = append(head, line[from:colon])
+
+	prop := icalProperty{
+		Name:   strings.ToUpper(head[0]),
+		Params: make(map[string]string),
+		Value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			prop.Params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+
+	return prop, nil
+}
+
+// parseICalTime parses a DATE or DATE-TIME property value
+func parseICalTime(prop icalProperty, loc *time.Location) (time.Time, bool, error) {
+	if prop.Params["VALUE"] == "DATE" || len(prop.Value) == 8 {
+		t, err := time.ParseInLocation("20060102", prop.Value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(prop.Value, "Z") {
+		t, err := time.Parse("20060102T150405Z", prop.Value)
+		return t, false, err
+	}
+
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if tzLoc, err := time.LoadLocation(tzid); err == nil {
+			loc = tzLoc
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", prop.Value, loc)
+	return t, false, err
+}
+
+// parseICalDuration parses an RFC 5545 duration such as PT1H30M or P1D
+func parseICalDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if strings.HasPrefix(value, "-") {
+		sign = -1
+	}
+	value = strings.TrimLeft(value, "+-")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	var total time.Duration
+	number := ""
+	for _, r := range value[1:] {
+		if r >= '0' && r <= '9' {
+			number += string(r)
+			continue
+		}
+		if r == 'T' {
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		number = ""
+		switch r {
+		case 'W':
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case 'D':
+			total += time.Duration(n) * 24 * time.Hour
+		case 'H':
+			total += time.Duration(n) * time.Hour
+		case 'M':
+			total += time.Duration(n) * time.Minute
+		case 'S':
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+	}
+
+	return sign * total, nil
+}
+
+// vEventToTask converts a parsed VEVENT into a task
+func vEventToTask(event icalComponent, loc *time.Location) (models.Task, error) {
+	var task models.Task
+
+	startProp, ok := event.Get("DTSTART")
+	if !ok {
+		return task, fmt.Errorf("VEVENT has no DTSTART")
+	}
+	start, allDay, err := parseICalTime(startProp, loc)
+	if err != nil {
+		return task, fmt.Errorf("invalid DTSTART: %v", err)
+	}
+
+	var end time.Time
+	if endProp, ok := event.Get("DTEND"); ok {
+		end, _, err = parseICalTime(endProp, loc)
+		if err != nil {
+			return task, fmt.Errorf("invalid DTEND: %v", err)
+		}
+	} else if durationProp, ok := event.Get("DURATION"); ok {
+		duration, err := parseICalDuration(durationProp.Value)
+		if err != nil {
+			return task, err
+		}
+		end = start.Add(duration)
+	} else if allDay {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		end = start
+	}
+
+	if allDay {
+		// DTEND of an all-day event is exclusive
+		end = end.Add(-time.Second)
+	}
+
+	task.Start = start.Format(time.RFC3339)
+	task.End = end.Format(time.RFC3339)
+	task.AllDay = allDay
+	task.TimeZone = startProp.Params["TZID"]
+
+	if prop, ok := event.Get("UID"); ok {
+		task.EventID = prop.Value
+	}
+	if prop, ok := event.Get("SUMMARY"); ok {
+		task.Summary = unescapeICalText(prop.Value)
+	}
+	if prop, ok := event.Get("DESCRIPTION"); ok {
+		task.Description = unescapeICalText(prop.Value)
+	}
+	if prop, ok := event.Get("LOCATION"); ok {
+		task.Location = unescapeICalText(prop.Value)
+	}
+	for _, prop := range event.Properties {
+		switch prop.Name {
+		case "RRULE", "EXDATE":
+			task.Recurrence = append(task.Recurrence, formatICalProperty(prop))
+		case "ATTENDEE":
+			task.Attendees = append(task.Attendees, models.Attendee{
+				Email:          strings.TrimPrefix(strings.TrimPrefix(prop.Value, "mailto:"), "MAILTO:"),
+				Name:           prop.Params["CN"],
+				ResponseStatus: partStatResponses[strings.ToUpper(prop.Params["PARTSTAT"])],
+			})
+		case "CONFERENCE":
+			task.Conference = &models.Conference{Link: prop.Value}
+		}
+	}
+
+	for _, component := range event.Components {
+		if component.Name != "VALARM" {
+			continue
+		}
+		if reminder, ok := vAlarmToReminder(component); ok {
+			task.Reminders = append(task.Reminders, reminder)
+		}
+	}
+
+	return task, nil
+}
+
+// vAlarmToReminder converts a VALARM into a reminder. Only alarms some time
+// before the start are reminders; ones set at a fixed time or relative to
+// the end are left out.
+func vAlarmToReminder(alarm icalComponent) (models.Reminder, bool) {
+	trigger, ok := alarm.Get("TRIGGER")
+	if !ok || trigger.Params["VALUE"] == "DATE-TIME" || strings.EqualFold(trigger.Params["RELATED"], "END") {
+		return models.Reminder{}, false
+	}
+	offset, err := parseICalDuration(trigger.Value)
+	if err != nil || offset > 0 {
+		return models.Reminder{}, false
+	}
+
+	method := models.ReminderPopup
+	if action, ok := alarm.Get("ACTION"); ok && strings.EqualFold(action.Value, "EMAIL") {
+		method = models.ReminderEmail
+	}
+	return models.Reminder{Method: method, Minutes: int(-offset / time.Minute)}, true
+}
+
+// partStatResponses maps iCalendar participation statuses to attendee responses
+var partStatResponses = map[string]string{
+	"NEEDS-ACTION": models.ResponseNeedsAction,
+	"ACCEPTED":     models.ResponseAccepted,
+	"TENTATIVE":    models.ResponseTentative,
+	"DECLINED":     models.ResponseDeclined,
+}
+
+// attendeeLine writes an attendee as an ATTENDEE property. Without updates
+// the server is told not to send the invitation itself.
+func attendeeLine(attendee models.Attendee, sendUpdates string) string {
+	partStat := "NEEDS-ACTION"
+	for name, response := range partStatResponses {
+		if response == attendee.ResponseStatus {
+			partStat = name
+		}
+	}
+
+	line := "ATTENDEE"
+	if attendee.Name != "" {
+		line += `;CN="` + strings.ReplaceAll(attendee.Name, `"`, "'") + `"`
+	}
+	line += ";PARTSTAT=" + partStat + ";RSVP=TRUE"
+	if sendUpdates == models.SendUpdatesNone {
+		line += ";SCHEDULE-AGENT=CLIENT"
+	}
+	return line + ":mailto:" + attendee.Email
+}
+
+// taskToICalendar serializes a task as a VCALENDAR containing one VEVENT
+func taskToICalendar(uid string, task models.Task) (string, error) {
+	if _, err := task.ParseTime(); err != nil {
+		return "", err
+	}
+
+	calendar := icalComponent{Name: "VCALENDAR", Properties: []icalProperty{
+		{Name: "VERSION", Value: "2.0"},
+		{Name: "PRODID", Value: "-//LLM Calendar Assistant//EN"},
+	}}
+	event := icalComponent{Name: "VEVENT", Properties: []icalProperty{
+		{Name: "UID", Value: uid},
+		{Name: "SUMMARY", Value: escapeICalText(task.Summary)},
+	}}
+	if err := patchVEvent(&event, task); err != nil {
+		return "", err
+	}
+	calendar.Components = append(calendar.Components, event)
+	addVTimezones(&calendar)
+
+	return formatICalendar(calendar), nil
+}
+
+// patchVEvent applies the non-empty fields of task to a VEVENT and leaves its
+// other properties and components alone. Timed events keep the wall clock of
+// their timezone, so a weekly event stays at the same local time across DST
+// changes: the event's own TZID, or else task.TimeZone.
+func patchVEvent(event *icalComponent, task models.Task) error {
+	if task.Start != "" || task.End != "" {
+		var zone *time.Location
+		if !task.AllDay {
+			zone = eventZone(event, task.TimeZone)
+		}
+
+		if task.Start != "" {
+			start, err := time.Parse(time.RFC3339, task.Start)
+			if err != nil {
+				return fmt.Errorf("invalid start time: %v", err)
+			}
+			event.Set(icalTimeProperty("DTSTART", start, task.AllDay, zone))
+		}
+		if task.End != "" {
+			end, err := time.Parse(time.RFC3339, task.End)
+			if err != nil {
+				return fmt.Errorf("invalid end time: %v", err)
+			}
+			if task.AllDay {
+				// DTEND of an all-day event is exclusive
+				end = end.Add(-time.Second).AddDate(0, 0, 1)
+			}
+			event.Remove("DURATION")
+			event.Set(icalTimeProperty("DTEND", end, task.AllDay, zone))
+		}
+	}
+
+	event.Set(icalProperty{Name: "DTSTAMP", Value: time.Now().UTC().Format("20060102T150405Z")})
+	if task.Summary != "" {
+		event.Set(icalProperty{Name: "SUMMARY", Value: escapeICalText(task.Summary)})
+	}
+	if task.Description != "" {
+		event.Set(icalProperty{Name: "DESCRIPTION", Value: escapeICalText(task.Description)})
+	}
+	if task.Location != "" {
+		event.Set(icalProperty{Name: "LOCATION", Value: escapeICalText(task.Location)})
+	}
+	if task.IsRecurring() {
+		event.Remove("RRULE", "RDATE", "EXDATE")
+		for _, line := range task.Recurrence {
+			prop, err := parseICalLine(strings.TrimSpace(line))
+			if err != nil {
+				return err
+			}
+			event.Properties = append(event.Properties, prop)
+		}
+	}
+	if len(task.Attendees) > 0 {
+		event.Remove("ATTENDEE")
+		for _, attendee := range task.Attendees {
+			if attendee.Email == "" {
+				continue
+			}
+			prop, err := parseICalLine(attendeeLine(attendee, task.SendUpdates))
+			if err != nil {
+				return err
+			}
+			event.Properties = append(event.Properties, prop)
+		}
+	}
+	if task.Conference != nil && task.Conference.Link != "" {
+		event.Set(icalProperty{Name: "CONFERENCE", Params: map[string]string{"VALUE": "URI", "FEATURE": "VIDEO"}, Value: task.Conference.Link})
+	}
+	if len(task.Reminders) > 0 {
+		summary := task.Summary
+		if prop, ok := event.Get("SUMMARY"); ok && summary == "" {
+			summary = unescapeICalText(prop.Value)
+		}
+
+		var components []icalComponent
+		for _, component := range event.Components {
+			if component.Name != "VALARM" {
+				components = append(components, component)
+			}
+		}
+		for _, reminder := range task.Reminders {
+			components = append(components, vAlarm(reminder, summary))
+		}
+		event.Components = components
+	}
+
+	return nil
+}
+
+// clearVEvent removes the properties and alarms of a VEVENT that a task
+// holds, apart from its times, so patching a task in replaces them
+func clearVEvent(event *icalComponent) {
+	event.Remove("SUMMARY", "DESCRIPTION", "LOCATION", "RRULE", "RDATE", "EXDATE", "ATTENDEE", "CONFERENCE")
+
+	var components []icalComponent
+	for _, component := range event.Components {
+		if component.Name != "VALARM" {
+			components = append(components, component)
+		}
+	}
+	event.Components = components
+}
+
+// bumpSequence counts a change to an event in its SEQUENCE, so attendees'
+// calendars know it is newer than what they have
+func bumpSequence(event *icalComponent) {
+	sequence := 0
+	if prop, ok := event.Get("SEQUENCE"); ok {
+		sequence, _ = strconv.Atoi(strings.TrimSpace(prop.Value))
+	}
+	event.Set(icalProperty{Name: "SEQUENCE", Value: strconv.Itoa(sequence + 1)})
+}
+
+// vAlarm converts a reminder into a VALARM. Email alarms go to the
+// calendar's owner, as the server decides.
+func vAlarm(reminder models.Reminder, summary string) icalComponent {
+	action := "DISPLAY"
+	if reminder.Method == models.ReminderEmail {
+		action = "EMAIL"
+	}
+
+	alarm := icalComponent{Name: "VALARM", Properties: []icalProperty{
+		{Name: "ACTION", Value: action},
+		{Name: "TRIGGER", Value: fmt.Sprintf("-PT%dM", reminder.Minutes)},
+	}}
+	if action == "EMAIL" {
+		alarm.Properties = append(alarm.Properties, icalProperty{Name: "SUMMARY", Value: escapeICalText(summary)})
+	}
+	alarm.Properties = append(alarm.Properties, icalProperty{Name: "DESCRIPTION", Value: escapeICalText(summary)})
+	return alarm
+}
+
+// icalTimeProperty writes t as a DATE, as a wall-clock time in zone, or,
+// without a zone, in UTC
+func icalTimeProperty(name string, t time.Time, allDay bool, zone *time.Location) icalProperty {
+	switch {
+	case allDay:
+		return icalProperty{Name: name, Params: map[string]string{"VALUE": "DATE"}, Value: t.Format("20060102")}
+	case zone != nil:
+		return icalProperty{Name: name, Params: map[string]string{"TZID": zone.String()}, Value: t.In(zone).Format("20060102T150405")}
+	}
+	return icalProperty{Name: name, Value: t.UTC().Format("20060102T150405Z")}
+}
+
+// eventZone returns the timezone of an event's DTSTART, or the zone named
+// fallback when the event is in UTC, in floating time or in a zone that
+// can't be loaded
+func eventZone(event *icalComponent, fallback string) *time.Location {
+	if prop, ok := event.Get("DTSTART"); ok {
+		if zone := icalZone(prop.Params["TZID"]); zone != nil {
+			return zone
+		}
+	}
+	return icalZone(fallback)
+}
+
+// addVTimezones adds a VTIMEZONE for each timezone the events of calendar
+// start in that it doesn't define yet
+func addVTimezones(calendar *icalComponent) {
+	defined := make(map[string]bool)
+	for _, component := range calendar.Components {
+		if prop, ok := component.Get("TZID"); ok && component.Name == "VTIMEZONE" {
+			defined[prop.Value] = true
+		}
+	}
+
+	var timezones []icalComponent
+	for _, component := range calendar.Components {
+		prop, ok := component.Get("DTSTART")
+		if !ok || component.Name != "VEVENT" || defined[prop.Params["TZID"]] {
+			continue
+		}
+		zone := icalZone(prop.Params["TZID"])
+		if zone == nil {
+			continue
+		}
+		start, _, err := parseICalTime(prop, zone)
+		if err != nil {
+			continue
+		}
+		timezones = append(timezones, vTimezone(zone, start))
+		defined[prop.Params["TZID"]] = true
+	}
+	calendar.Components = append(timezones, calendar.Components...)
+}
+
+// icalZone loads the IANA timezone an event is written in. UTC, unknown
+// zones and none are written as UTC times instead.
+func icalZone(name string) *time.Location {
+	if name == "" || name == "UTC" {
+		return nil
+	}
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return zone
+}
+
+// vTimezone describes zone as a VTIMEZONE. The transitions of the year
+// before around are written as yearly rules, which holds for as long as
+// the zone's rules stay the same. A zone without transitions gets a single
+// standard time.
+func vTimezone(zone *time.Location, around time.Time) icalComponent {
+	timezone := icalComponent{Name: "VTIMEZONE", Properties: []icalProperty{{Name: "TZID", Value: zone.String()}}}
+
+	t := time.Date(around.In(zone).Year()-1, time.January, 1, 0, 0, 0, 0, zone)
+	yearEnd := t.AddDate(1, 0, 0)
+	var transitions []time.Time
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(yearEnd) {
+			break
+		}
+		transitions = append(transitions, end)
+		t = end
+	}
+
+	if len(transitions) == 0 {
+		name, offset := t.Zone()
+		timezone.Components = append(timezone.Components, icalComponent{Name: "STANDARD", Properties: []icalProperty{
+			{Name: "DTSTART", Value: "19700101T000000"},
+			{Name: "TZOFFSETFROM", Value: formatUTCOffset(offset)},
+			{Name: "TZOFFSETTO", Value: formatUTCOffset(offset)},
+			{Name: "TZNAME", Value: name},
+		}})
+	}
+	for _, transition := range transitions {
+		_, before := transition.Add(-time.Second).Zone()
+		name, after := transition.Zone()
+		observance := icalComponent{Name: "STANDARD"}
+		if transition.IsDST() {
+			observance.Name = "DAYLIGHT"
+		}
+
+		// The onset is in the local time it is changed from
+		onset := transition.In(time.FixedZone("", before))
+		week := (onset.Day()-1)/7 + 1
+		if onset.AddDate(0, 0, 7).Month() != onset.Month() {
+			week = -1
+		}
+
+		observance.Properties = append(observance.Properties, icalProperty{Name: "DTSTART", Value: onset.Format("20060102T150405")})
+		if len(transitions) == 2 {
+			observance.Properties = append(observance.Properties, icalProperty{Name: "RRULE", Value: fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s",
+				int(onset.Month()), week, strings.ToUpper(onset.Weekday().String()[:2]))})
+		}
+		observance.Properties = append(observance.Properties,
+			icalProperty{Name: "TZOFFSETFROM", Value: formatUTCOffset(before)},
+			icalProperty{Name: "TZOFFSETTO", Value: formatUTCOffset(after)},
+			icalProperty{Name: "TZNAME", Value: name})
+		timezone.Components = append(timezone.Components, observance)
+	}
+
+	return timezone
+}
+
+// formatUTCOffset formats an offset in seconds as +hhmm or -hhmm
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// formatICalProperty writes a property back as a content line
+func formatICalProperty(prop icalProperty) string {
+	var b strings.Builder
+	b.WriteString(prop.Name)
+
+	var names []string
+	for name := range prop.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := prop.Params[name]
+		if strings.ContainsAny(value, ":;,") {
+			value = `"` + value + `"`
+		}
+		b.WriteString(";" + name + "=" + value)
+	}
+
+	b.WriteString(":" + prop.Value)
+	return b.String()
+}
+
+// formatICalendar writes components, with their properties and nested
+// components, as iCalendar data
+func formatICalendar(components ...icalComponent) string {
+	var b strings.Builder
+	writeLine := func(line string) {
+		b.WriteString(foldICalLine(line))
+		b.WriteString("\r\n")
+	}
+
+	var write func(component icalComponent)
+	write = func(component icalComponent) {
+		writeLine("BEGIN:" + component.Name)
+		for _, prop := range component.Properties {
+			writeLine(formatICalProperty(prop))
+		}
+		for _, nested := range component.Components {
+			write(nested)
+		}
+		writeLine("END:" + component.Name)
+	}
+	for _, component := range components {
+		write(component)
+	}
+	return b.String()
+}
+
+// foldICalLine folds lines longer than 75 octets
+func foldICalLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// escapeICalText escapes a TEXT value
+func escapeICalText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+// unescapeICalText reverses escapeICalText
+func unescapeICalText(text string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(text)
+}
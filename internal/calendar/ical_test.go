@@ -0,0 +1,255 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+func TestParseICalendar(t *testing.T) {
+	components, err := parseICalendar(standupResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(components) != 1 || components[0].Name != "VCALENDAR" {
+		t.Fatalf("got %d top-level components, want one VCALENDAR", len(components))
+	}
+
+	var names []string
+	for _, component := range components[0].Components {
+		names = append(names, component.Name)
+	}
+	if want := []string{"VTIMEZONE", "VEVENT", "VEVENT"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got components %v, want %v", names, want)
+	}
+
+	organizer, _ := components[0].Components[1].Get("ORGANIZER")
+	if organizer.Params["CN"] != "Lead, Team" || organizer.Value != "mailto:lead@example.com" {
+		t.Errorf("got organizer %+v", organizer)
+	}
+
+	// Written back, the resource parses to the same tree
+	again, err := parseICalendar(formatICalendar(components...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, components) {
+		t.Errorf("tree changed when written back:\n%s", formatICalendar(again...))
+	}
+
+	for _, data := range []string{
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\n",
+		"BEGIN:VCALENDAR\nnot a property\nEND:VCALENDAR\n",
+	} {
+		if _, err := parseICalendar(data); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestVEventToTask(t *testing.T) {
+	data := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:review@example.com
+DTSTART:20261020T140000Z
+DURATION:PT1H30M
+SUMMARY:Design review\, round 2
+DESCRIPTION:Bring the mockups\nand the notes
+LOCATION:Room 4
+RRULE:FREQ=WEEKLY;COUNT=3
+EXDATE:20261027T140000Z
+ATTENDEE;CN=Sam;PARTSTAT=ACCEPTED:mailto:sam@example.com
+ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:alex@example.com
+CONFERENCE;VALUE=URI;FEATURE=VIDEO:https://meet.example.com/review
+BEGIN:VALARM
+ACTION:EMAIL
+TRIGGER:-PT1H
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:offsite@example.com
+DTSTART;VALUE=DATE:20261022
+DTEND;VALUE=DATE:20261024
+SUMMARY:Offsite
+END:VEVENT
+END:VCALENDAR
+`
+	events, err := parseVEvents(strings.ReplaceAll(data, "\n", "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+
+	review, err := vEventToTask(events[0], time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.Task{
+		EventID:     "review@example.com",
+		Summary:     "Design review, round 2",
+		Description: "Bring the mockups\nand the notes",
+		Location:    "Room 4",
+		Start:       "2026-10-20T14:00:00Z",
+		End:         "2026-10-20T15:30:00Z",
+		Recurrence:  []string{"RRULE:FREQ=WEEKLY;COUNT=3", "EXDATE:20261027T140000Z"},
+		Attendees: []models.Attendee{
+			{Email: "sam@example.com", Name: "Sam", ResponseStatus: models.ResponseAccepted},
+			{Email: "alex@example.com", ResponseStatus: models.ResponseNeedsAction},
+		},
+		Conference: &models.Conference{Link: "https://meet.example.com/review"},
+		Reminders:  []models.Reminder{{Method: models.ReminderEmail, Minutes: 60}},
+	}
+	if !reflect.DeepEqual(review, want) {
+		t.Errorf("got  %+v\nwant %+v", review, want)
+	}
+
+	offsite, err := vEventToTask(events[1], time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !offsite.AllDay || offsite.Start != "2026-10-22T00:00:00Z" || offsite.End != "2026-10-23T23:59:59Z" {
+		t.Errorf("all-day event spans %s to %s (all day %v)", offsite.Start, offsite.End, offsite.AllDay)
+	}
+}
+
+func TestTaskToICalendarRoundTrip(t *testing.T) {
+	tests := []models.Task{
+		{
+			Summary:     "Planning; Q4, draft",
+			Description: "Agenda:\n1. Budget",
+			Location:    "HQ",
+			Start:       "2026-10-20T09:00:00-04:00",
+			End:         "2026-10-20T10:00:00-04:00",
+			TimeZone:    "America/New_York",
+			Recurrence:  []string{"RRULE:FREQ=WEEKLY;BYDAY=TU;COUNT=4"},
+			Attendees:   []models.Attendee{{Email: "sam@example.com", Name: "Sam Lee", ResponseStatus: models.ResponseNeedsAction}},
+			Conference:  &models.Conference{Link: "https://meet.example.com/planning"},
+			Reminders:   []models.Reminder{{Method: models.ReminderPopup, Minutes: 10}, {Method: models.ReminderEmail, Minutes: 1440}},
+		},
+		{
+			Summary: "Focus time",
+			Start:   "2026-10-21T13:00:00Z",
+			End:     "2026-10-21T15:00:00Z",
+		},
+		{
+			Summary: "Conference",
+			Start:   "2026-10-22T00:00:00Z",
+			End:     "2026-10-23T23:59:59Z",
+			AllDay:  true,
+		},
+	}
+
+	for _, task := range tests {
+		data, err := taskToICalendar("uid-1", task)
+		if err != nil {
+			t.Errorf("%s: %v", task.Summary, err)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("%s: line longer than 75 octets: %q", task.Summary, line)
+			}
+		}
+
+		events, err := parseVEvents(data)
+		if err != nil || len(events) != 1 {
+			t.Errorf("%s: parsed %d events, %v", task.Summary, len(events), err)
+			continue
+		}
+		got, err := vEventToTask(events[0], time.UTC)
+		if err != nil {
+			t.Errorf("%s: %v", task.Summary, err)
+			continue
+		}
+
+		want := task
+		want.EventID = "uid-1"
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: round trip changed the task\ngot  %+v\nwant %+v\n%s", task.Summary, got, want, data)
+		}
+	}
+}
+
+func TestPatchVEvent(t *testing.T) {
+	components, err := parseICalendar(standupResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := components[0].Components[1]
+
+	err = patchVEvent(&event, models.Task{
+		Location:  "Room 2",
+		Reminders: []models.Reminder{{Method: models.ReminderPopup, Minutes: 15}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bumpSequence(&event)
+
+	task, err := vEventToTask(event, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Summary != "Standup" || task.Location != "Room 2" || !task.IsRecurring() || task.Start != "2026-10-30T09:00:00-04:00" {
+		t.Errorf("got %+v", task)
+	}
+	if !reflect.DeepEqual(task.Reminders, []models.Reminder{{Method: models.ReminderPopup, Minutes: 15}}) {
+		t.Errorf("reminders %+v should replace the existing alarm", task.Reminders)
+	}
+	for name, value := range map[string]string{"SEQUENCE": "3", "STATUS": "CONFIRMED", "X-EXAMPLE-COLOR": "blue"} {
+		if prop, _ := event.Get(name); prop.Value != value {
+			t.Errorf("%s is %q, want %q", name, prop.Value, value)
+		}
+	}
+}
+
+func TestVTimezone(t *testing.T) {
+	tests := []struct {
+		zone  string
+		lines []string
+	}{
+		{"America/New_York", []string{
+			"BEGIN:DAYLIGHT", "DTSTART:20250309T020000", "RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU", "TZOFFSETFROM:-0500", "TZOFFSETTO:-0400",
+			"BEGIN:STANDARD", "DTSTART:20251102T020000", "RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU", "TZOFFSETFROM:-0400", "TZOFFSETTO:-0500",
+		}},
+		{"Europe/Berlin", []string{"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU", "RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU"}},
+		{"Asia/Kolkata", []string{"BEGIN:STANDARD", "DTSTART:19700101T000000", "TZOFFSETFROM:+0530", "TZOFFSETTO:+0530"}},
+	}
+
+	for _, test := range tests {
+		zone := mustLoadLocation(t, test.zone)
+		data := formatICalendar(vTimezone(zone, time.Date(2026, 10, 17, 0, 0, 0, 0, zone)))
+		if !strings.HasPrefix(data, "BEGIN:VTIMEZONE\r\nTZID:"+test.zone+"\r\n") {
+			t.Errorf("%s: got\n%s", test.zone, data)
+		}
+		for _, line := range test.lines {
+			if !strings.Contains(data, line+"\r\n") {
+				t.Errorf("%s: no %q in\n%s", test.zone, line, data)
+			}
+		}
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 60)
+	folded := foldICalLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line longer than 75 octets: %q", part)
+		}
+	}
+
+	events, err := parseVEvents("BEGIN:VEVENT\r\n" + folded + "\r\nEND:VEVENT\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prop, _ := events[0].Get("DESCRIPTION"); prop.Value != strings.Repeat("é", 60) {
+		t.Errorf("unfolded to %q", prop.Value)
+	}
+}
@@ -0,0 +1,164 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// maxJournalEntries bounds the journal file; the oldest entries are dropped first
+const maxJournalEntries = 500
+
+// Journal operations
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// JournalEntry records one change made to a calendar, with the full event
+// payload needed to reverse it
+type JournalEntry struct {
+	ID         int          `json:"id"`
+	Time       time.Time    `json:"time"`
+	Operation  string       `json:"operation"` // "create", "update" or "delete"
+	CalendarID string       `json:"calendar_id"`
+	Event      models.Task  `json:"event"`              // created or deleted event, or the new version
+	Previous   *models.Task `json:"previous,omitempty"` // version before an update
+	Group      int          `json:"group,omitempty"`    // ID of the first entry of a change made of several
+	Undone     bool         `json:"undone,omitempty"`
+}
+
+// ChangeID identifies the change an entry is part of. Entries recorded
+// together share it and are undone together.
+func (e JournalEntry) ChangeID() int {
+	if e.Group != 0 {
+		return e.Group
+	}
+	return e.ID
+}
+
+// Journal is a persistent log of calendar changes that can be undone
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	entries []JournalEntry
+	nextID  int
+}
+
+// OpenJournal loads the journal stored at path, or starts an empty one if
+// the file does not exist yet
+func OpenJournal(path string) (*Journal, error) {
+	journal := &Journal{path: path, nextID: 1}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read undo journal: %v", err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &journal.entries); err != nil {
+			return nil, fmt.Errorf("unable to parse undo journal: %v", err)
+		}
+	}
+	for _, entry := range journal.entries {
+		if entry.ID >= journal.nextID {
+			journal.nextID = entry.ID + 1
+		}
+	}
+
+	return journal, nil
+}
+
+// Record appends the entries of one change and saves the journal. Several
+// entries, such as an occurrence split off a series and the series, are
+// grouped so they are undone together.
+func (j *Journal) Record(entries ...JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	group := 0
+	if len(entries) > 1 {
+		group = j.nextID
+	}
+	for _, entry := range entries {
+		entry.ID = j.nextID
+		j.nextID++
+		entry.Group = group
+		if entry.Time.IsZero() {
+			entry.Time = time.Now()
+		}
+		j.entries = append(j.entries, entry)
+	}
+	if len(j.entries) > maxJournalEntries {
+		j.entries = j.entries[len(j.entries)-maxJournalEntries:]
+	}
+
+	return j.save()
+}
+
+// Recent returns the entries of up to n changes that have not been undone,
+// newest first
+func (j *Journal) Recent(n int) []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entries []JournalEntry
+	changes := 0
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+		if entry.Undone {
+			continue
+		}
+		if len(entries) == 0 || entry.ChangeID() != entries[len(entries)-1].ChangeID() {
+			if changes == n {
+				break
+			}
+			changes++
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// MarkUndone flags an entry as undone and saves the journal
+func (j *Journal) MarkUndone(id int) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.entries {
+		if j.entries[i].ID == id {
+			j.entries[i].Undone = true
+			return j.save()
+		}
+	}
+	return fmt.Errorf("journal entry %d not found", id)
+}
+
+// save writes the journal atomically. The caller must hold j.mu.
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(j.path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("unable to create undo journal directory: %v", err)
+		}
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write undo journal: %v", err)
+	}
+	return os.Rename(tmp, j.path)
+}
@@ -0,0 +1,257 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// MemoryBackend implements CalendarBackend with an in-process event store.
+// It lets the assistant run without a Google account.
+type MemoryBackend struct {
+	mu     sync.RWMutex
+	events map[string]map[string]models.Task // calendarID -> eventID -> event
+	nextID int
+}
+
+// NewMemoryBackend creates a new, empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		events: make(map[string]map[string]models.Task),
+	}
+}
+
+// GetName returns the backend name
+func (m *MemoryBackend) GetName() string {
+	return "In-Memory Calendar"
+}
+
+// ListEvents returns stored events overlapping [timeMin, timeMax)
+func (m *MemoryBackend) ListEvents(calendarID string, timeMin, timeMax time.Time) ([]models.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range m.events[calendarID] {
+		// Recurring events are returned as their single occurrences
+		occurrences, err := ExpandRecurrence(task, timeMin, timeMax)
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, occurrences...)
+	}
+
+	sortTasksByStart(tasks)
+
+	return tasks, nil
+}
+
+// GetEvent returns a single event by ID
+func (m *MemoryBackend) GetEvent(calendarID, eventID string, loc *time.Location) (*models.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	task, exists := m.events[calendarID][eventID]
+	if !exists {
+		return m.getOccurrence(calendarID, eventID, loc)
+	}
+	return &task, nil
+}
+
+// getOccurrence resolves an instance ID of a recurring event, which has the
+// form seriesID_start; an all-day start is a date in loc
+func (m *MemoryBackend) getOccurrence(calendarID, eventID string, loc *time.Location) (*models.Task, error) {
+	seriesID, start, _, ok := splitInstanceEventID(eventID, loc)
+	if !ok {
+		return nil, fmt.Errorf("event %s not found", eventID)
+	}
+
+	series, exists := m.events[calendarID][seriesID]
+	if !exists || !series.IsRecurring() {
+		return nil, fmt.Errorf("event %s not found", eventID)
+	}
+	return findOccurrence(series, eventID, start)
+}
+
+// InsertEvent stores a new event and assigns it an ID
+func (m *MemoryBackend) InsertEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	if _, err := task.ParseTime(); err != nil {
+		return nil, err
+	}
+	if task.IsRecurring() {
+		if err := ValidateRecurrence(task); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	task.EventID = fmt.Sprintf("mem-%d", m.nextID)
+	task.Attendees = invite(task.Attendees)
+	task.Conference = memoryConference(task.EventID, task.Conference)
+	task.SendUpdates = "" // how attendees were told isn't part of the event
+
+	if m.events[calendarID] == nil {
+		m.events[calendarID] = make(map[string]models.Task)
+	}
+	m.events[calendarID][task.EventID] = task
+
+	return &task, nil
+}
+
+// UpdateEvent replaces the non-empty fields of the event identified by task.EventID
+func (m *MemoryBackend) UpdateEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.events[calendarID][task.EventID]
+	if !exists {
+		return nil, fmt.Errorf("event %s not found", task.EventID)
+	}
+
+	if task.Summary != "" {
+		existing.Summary = task.Summary
+	}
+	if task.Start != "" {
+		existing.Start = task.Start
+	}
+	if task.End != "" {
+		existing.End = task.End
+	}
+	if task.Description != "" {
+		existing.Description = task.Description
+	}
+	if task.Location != "" {
+		existing.Location = task.Location
+	}
+	if task.IsRecurring() {
+		existing.Recurrence = task.Recurrence
+	}
+	if len(task.Attendees) > 0 {
+		existing.Attendees = invite(task.Attendees)
+	}
+	if task.Conference != nil {
+		existing.Conference = memoryConference(existing.EventID, task.Conference)
+	}
+	if len(task.Reminders) > 0 {
+		existing.Reminders = task.Reminders
+	}
+
+	if _, err := existing.ParseTime(); err != nil {
+		return nil, err
+	}
+
+	m.events[calendarID][task.EventID] = existing
+	return &existing, nil
+}
+
+// ReplaceEvent overwrites the event identified by task.EventID with task
+func (m *MemoryBackend) ReplaceEvent(calendarID string, task models.Task, loc *time.Location) (*models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.events[calendarID][task.EventID]; !exists {
+		return nil, fmt.Errorf("event %s not found", task.EventID)
+	}
+	if _, err := task.ParseTime(); err != nil {
+		return nil, err
+	}
+
+	if len(task.Attendees) > 0 {
+		task.Attendees = invite(task.Attendees)
+	}
+	task.SendUpdates = ""
+	m.events[calendarID][task.EventID] = task
+	return &task, nil
+}
+
+// invite marks attendees who haven't responded yet as awaiting a response.
+// Nobody is emailed; setting an attendee's response_status through an
+// update stands in for their reply.
+func invite(attendees []models.Attendee) []models.Attendee {
+	invited := make([]models.Attendee, len(attendees))
+	for i, attendee := range attendees {
+		if attendee.ResponseStatus == "" {
+			attendee.ResponseStatus = models.ResponseNeedsAction
+		}
+		invited[i] = attendee
+	}
+	return invited
+}
+
+// memoryConference stands in for a video call the calendar adds, with a
+// link that doesn't lead anywhere
+func memoryConference(eventID string, conference *models.Conference) *models.Conference {
+	if conference == nil {
+		return nil
+	}
+	if conference.Create && conference.Link == "" {
+		return &models.Conference{Link: "https://meet.invalid/" + eventID}
+	}
+	return &models.Conference{Link: conference.Link}
+}
+
+// DeleteEvent removes an event by ID
+func (m *MemoryBackend) DeleteEvent(calendarID, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.events[calendarID][eventID]; !exists {
+		return fmt.Errorf("event %s not found", eventID)
+	}
+	delete(m.events[calendarID], eventID)
+	return nil
+}
+
+// ListCalendars returns the primary calendar plus every calendar holding events
+func (m *MemoryBackend) ListCalendars() ([]models.CalendarInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	calendars := []models.CalendarInfo{
+		{ID: DefaultCalendarID, Name: "Primary", Primary: true, AccessRole: "owner"},
+	}
+
+	var ids []string
+	for id := range m.events {
+		if id != DefaultCalendarID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		calendars = append(calendars, models.CalendarInfo{ID: id, Name: id, AccessRole: "owner"})
+	}
+
+	return calendars, nil
+}
+
+// FreeBusy returns the busy times of the events stored under each ID, so
+// other people's calendars can be stood in for by calendars named after
+// their email. Every ID can be looked up; one without events is free.
+func (m *MemoryBackend) FreeBusy(ids []string, timeMin, timeMax time.Time) (map[string][]models.TimeSlot, error) {
+	busy := make(map[string][]models.TimeSlot)
+	for _, id := range ids {
+		events, err := m.ListEvents(id, timeMin, timeMax)
+		if err != nil {
+			return nil, err
+		}
+
+		slots := []models.TimeSlot{}
+		for _, event := range events {
+			parsed, err := event.ParseTime()
+			if err != nil {
+				continue
+			}
+			slots = append(slots, models.TimeSlot{Start: parsed.StartTime, End: parsed.EndTime})
+		}
+		busy[id] = slots
+	}
+	return busy, nil
+}
@@ -0,0 +1,258 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// Conflict severities
+const (
+	SeverityNone = "none" // the events may overlap
+	SeveritySoft = "soft" // reported, but the new event is still allowed
+	SeverityHard = "hard" // the new event is not allowed
+)
+
+// anyCategory matches every event in an overlap rule
+const anyCategory = "*"
+
+// ConflictPolicy decides which events may overlap and how much time must
+// separate the ones that may not. A nil policy is the default policy.
+type ConflictPolicy struct {
+	Categories []EventCategory `json:"categories"`
+	Rules      []OverlapRule   `json:"rules"` // the first matching rule wins
+	// Severity of overlaps no rule covers; empty means hard
+	DefaultSeverity string `json:"default_severity,omitempty"`
+	// Time kept between events that may not overlap, and between events at
+	// different locations
+	MinBufferMinutes int `json:"min_buffer_minutes,omitempty"`
+	TravelMinutes    int `json:"travel_minutes,omitempty"`
+	// Severity of too little time between events; empty means soft
+	BufferSeverity string `json:"buffer_severity,omitempty"`
+}
+
+// EventCategory groups events by words in their title, or all-day events
+type EventCategory struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords,omitempty"` // found anywhere in the title, ignoring case
+	AllDay   bool     `json:"all_day,omitempty"`  // every all-day event is in the category
+}
+
+// OverlapRule sets the severity of an overlap between events of two
+// categories, in either order
+type OverlapRule struct {
+	Categories [2]string `json:"categories"` // category names, or "*" for any event
+	Severity   string    `json:"severity"`
+	Inside     bool      `json:"inside,omitempty"`      // only when the first category's event lies within the second's
+	MaxMinutes int       `json:"max_minutes,omitempty"` // only when the first category's event is at most this long
+}
+
+// PolicyConflict is a reason two events shouldn't be where they are
+type PolicyConflict struct {
+	Severity string
+	Reason   string // how the new event relates to the existing one, e.g. "overlaps"
+}
+
+// DefaultConflictPolicy lets lunch happen inside work, and only warns about
+// overlapping all-day events, focus blocks and tentative holds
+func DefaultConflictPolicy() *ConflictPolicy {
+	return &ConflictPolicy{
+		Categories: []EventCategory{
+			{Name: "lunch", Keywords: []string{"lunch", "break", "meal"}},
+			{Name: "work", Keywords: []string{"work", "office", "job"}},
+			{Name: "focus", Keywords: []string{"focus", "deep work"}},
+			{Name: "hold", Keywords: []string{"hold", "tentative"}},
+			{Name: "all_day", AllDay: true},
+		},
+		Rules: []OverlapRule{
+			{Categories: [2]string{"lunch", "work"}, Severity: SeverityNone, Inside: true, MaxMinutes: 120},
+			{Categories: [2]string{"all_day", anyCategory}, Severity: SeveritySoft},
+			{Categories: [2]string{"focus", anyCategory}, Severity: SeveritySoft},
+			{Categories: [2]string{"hold", anyCategory}, Severity: SeveritySoft},
+		},
+	}
+}
+
+// LoadConflictPolicy reads the policy stored at path, or returns the
+// default policy if path is empty or the file does not exist
+func LoadConflictPolicy(path string) (*ConflictPolicy, error) {
+	if path == "" {
+		return DefaultConflictPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultConflictPolicy(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read conflict policy: %v", err)
+	}
+
+	var policy ConflictPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("unable to parse conflict policy: %v", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid conflict policy %s: %v", path, err)
+	}
+	return &policy, nil
+}
+
+// Validate checks that rules name known categories and severities
+func (p *ConflictPolicy) Validate() error {
+	names := map[string]bool{anyCategory: true}
+	for _, category := range p.Categories {
+		if category.Name == "" || category.Name == anyCategory {
+			return fmt.Errorf("every category needs a name other than %q", anyCategory)
+		}
+		names[category.Name] = true
+	}
+
+	for i, rule := range p.Rules {
+		for _, name := range rule.Categories {
+			if !names[name] {
+				return fmt.Errorf("rule %d: unknown category %q", i+1, name)
+			}
+		}
+		if !validSeverity(rule.Severity) {
+			return fmt.Errorf("rule %d: severity must be none, soft or hard", i+1)
+		}
+	}
+	for _, severity := range []string{p.DefaultSeverity, p.BufferSeverity} {
+		if severity != "" && !validSeverity(severity) {
+			return fmt.Errorf("invalid severity %q, expected none, soft or hard", severity)
+		}
+	}
+	if p.MinBufferMinutes < 0 || p.TravelMinutes < 0 {
+		return fmt.Errorf("buffer and travel minutes can't be negative")
+	}
+	return nil
+}
+
+// Check returns how a new event conflicts with an existing one. Overlaps
+// take the severity of the first matching rule. Events that may not overlap
+// also need the minimum buffer between them, or the travel time when their
+// locations differ. Events that don't conflict get SeverityNone.
+func (p *ConflictPolicy) Check(event, existing models.Task) PolicyConflict {
+	if p == nil {
+		p = DefaultConflictPolicy()
+	}
+
+	start, end, ok := taskTimes(event)
+	existingStart, existingEnd, ok2 := taskTimes(existing)
+	if !ok || !ok2 {
+		return PolicyConflict{Severity: SeverityNone} // If we can't parse, assume no conflict
+	}
+
+	severity := p.overlapSeverity(event, existing)
+	if start.Before(existingEnd) && end.After(existingStart) {
+		return PolicyConflict{Severity: severity, Reason: "overlaps"}
+	}
+	if severity == SeverityNone {
+		return PolicyConflict{Severity: SeverityNone}
+	}
+
+	// Time between the events
+	gap := start.Sub(existingEnd)
+	if !end.After(existingStart) {
+		gap = existingStart.Sub(end)
+	}
+
+	needed := time.Duration(p.MinBufferMinutes) * time.Minute
+	reason := fmt.Sprintf("is less than %d minutes from", p.MinBufferMinutes)
+	if travel := time.Duration(p.TravelMinutes) * time.Minute; travel > needed && differentPlaces(event, existing) {
+		needed = travel
+		reason = fmt.Sprintf("leaves less than %d minutes to travel to or from", p.TravelMinutes)
+	}
+	if gap < needed {
+		bufferSeverity := p.BufferSeverity
+		if bufferSeverity == "" {
+			bufferSeverity = SeveritySoft
+		}
+		return PolicyConflict{Severity: bufferSeverity, Reason: reason}
+	}
+	return PolicyConflict{Severity: SeverityNone}
+}
+
+// overlapSeverity returns how bad it is for two events to overlap
+func (p *ConflictPolicy) overlapSeverity(event, existing models.Task) string {
+	for _, rule := range p.Rules {
+		if p.ruleApplies(rule, event, existing) || p.ruleApplies(rule, existing, event) {
+			return rule.Severity
+		}
+	}
+	if p.DefaultSeverity != "" {
+		return p.DefaultSeverity
+	}
+	return SeverityHard
+}
+
+// ruleApplies checks a rule with first in its first category and second in
+// its second
+func (p *ConflictPolicy) ruleApplies(rule OverlapRule, first, second models.Task) bool {
+	if !p.inCategory(first, rule.Categories[0]) || !p.inCategory(second, rule.Categories[1]) {
+		return false
+	}
+
+	firstStart, firstEnd, ok := taskTimes(first)
+	secondStart, secondEnd, ok2 := taskTimes(second)
+	if !ok || !ok2 {
+		return false
+	}
+	if rule.Inside && !(firstStart.After(secondStart) && firstEnd.Before(secondEnd)) {
+		return false
+	}
+	if rule.MaxMinutes > 0 && firstEnd.Sub(firstStart) > time.Duration(rule.MaxMinutes)*time.Minute {
+		return false
+	}
+	return true
+}
+
+// inCategory checks whether an event belongs to the named category
+func (p *ConflictPolicy) inCategory(event models.Task, name string) bool {
+	if name == anyCategory {
+		return true
+	}
+	summary := strings.ToLower(event.Summary)
+	for _, category := range p.Categories {
+		if category.Name != name {
+			continue
+		}
+		if category.AllDay && event.AllDay {
+			return true
+		}
+		for _, keyword := range category.Keywords {
+			if strings.Contains(summary, strings.ToLower(keyword)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taskTimes parses an event's start and end
+func taskTimes(task models.Task) (time.Time, time.Time, bool) {
+	start, err := time.Parse(time.RFC3339, task.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse(time.RFC3339, task.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// differentPlaces checks whether two events are at known, different locations
+func differentPlaces(a, b models.Task) bool {
+	return a.Location != "" && b.Location != "" && !strings.EqualFold(strings.TrimSpace(a.Location), strings.TrimSpace(b.Location))
+}
+
+// validSeverity checks a severity name
+func validSeverity(severity string) bool {
+	return severity == SeverityNone || severity == SeveritySoft || severity == SeverityHard
+}
@@ -0,0 +1,118 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// event returns a task on 2026-10-20 between two "15:04" times
+func event(summary, start, end string) models.Task {
+	return models.Task{Summary: summary, Start: "2026-10-20T" + start + ":00Z", End: "2026-10-20T" + end + ":00Z"}
+}
+
+func TestConflictPolicyCheck(t *testing.T) {
+	buffered := &ConflictPolicy{MinBufferMinutes: 10, TravelMinutes: 30, BufferSeverity: SeverityHard}
+	atOffice := event("Client call", "11:05", "11:30")
+	atOffice.Location = "Office"
+	atCafe := event("Coffee", "10:00", "10:45")
+	atCafe.Location = "Cafe"
+	allDay := event("Conference", "00:00", "23:59")
+	allDay.AllDay = true
+
+	tests := []struct {
+		name     string
+		policy   *ConflictPolicy
+		event    models.Task
+		existing models.Task
+		severity string
+		reason   string
+	}{
+		{"plain overlap is hard", nil, event("Interview", "10:00", "11:00"), event("Standup", "10:30", "10:45"), SeverityHard, "overlaps"},
+		{"back to back is fine", nil, event("Interview", "10:00", "11:00"), event("Standup", "11:00", "11:15"), SeverityNone, ""},
+		{"lunch inside work", nil, event("Lunch", "12:00", "13:00"), event("Work", "09:00", "17:00"), SeverityNone, "overlaps"},
+		{"work around lunch, in either order", nil, event("Work", "09:00", "17:00"), event("Team lunch", "12:00", "13:00"), SeverityNone, "overlaps"},
+		{"long lunch is not a break", nil, event("Lunch", "11:00", "14:00"), event("Work", "09:00", "17:00"), SeverityHard, "overlaps"},
+		{"lunch across the end of work", nil, event("Lunch", "16:30", "17:30"), event("Work", "09:00", "17:00"), SeverityHard, "overlaps"},
+		{"focus block is soft", nil, event("Interview", "10:00", "11:00"), event("Focus time", "09:00", "12:00"), SeveritySoft, "overlaps"},
+		{"all-day event is soft", nil, event("Interview", "10:00", "11:00"), allDay, SeveritySoft, "overlaps"},
+		{"buffer too short", buffered, event("Interview", "10:00", "11:00"), event("Standup", "11:05", "11:15"), SeverityHard, "is less than 10 minutes from"},
+		{"buffer kept", buffered, event("Interview", "10:00", "11:00"), event("Standup", "11:10", "11:20"), SeverityNone, ""},
+		{"travel between places", buffered, atCafe, atOffice, SeverityHard, "leaves less than 30 minutes to travel to or from"},
+		{"unparseable times", nil, models.Task{Summary: "Interview", Start: "soon"}, event("Standup", "10:30", "10:45"), SeverityNone, ""},
+		{
+			"default severity",
+			&ConflictPolicy{DefaultSeverity: SeveritySoft},
+			event("Interview", "10:00", "11:00"), event("Standup", "10:30", "10:45"),
+			SeveritySoft, "overlaps",
+		},
+		{
+			"first matching rule wins",
+			&ConflictPolicy{
+				Categories: []EventCategory{{Name: "call", Keywords: []string{"call"}}},
+				Rules: []OverlapRule{
+					{Categories: [2]string{"call", anyCategory}, Severity: SeverityNone, MaxMinutes: 15},
+					{Categories: [2]string{"call", anyCategory}, Severity: SeveritySoft},
+				},
+			},
+			event("Quick call", "10:00", "10:30"), event("Standup", "10:15", "10:45"),
+			SeveritySoft, "overlaps",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.policy.Check(test.event, test.existing)
+		if got.Severity != test.severity || got.Reason != test.reason {
+			t.Errorf("%s: got %s %q, want %s %q", test.name, got.Severity, got.Reason, test.severity, test.reason)
+		}
+	}
+}
+
+func TestConflictPolicyValidate(t *testing.T) {
+	if err := DefaultConflictPolicy().Validate(); err != nil {
+		t.Errorf("default policy: %v", err)
+	}
+
+	tests := []ConflictPolicy{
+		{Categories: []EventCategory{{Name: anyCategory}}},
+		{Rules: []OverlapRule{{Categories: [2]string{"gym", anyCategory}, Severity: SeveritySoft}}},
+		{Rules: []OverlapRule{{Categories: [2]string{anyCategory, anyCategory}, Severity: "maybe"}}},
+		{DefaultSeverity: "blocking"},
+		{MinBufferMinutes: -5},
+	}
+	for _, policy := range tests {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", policy)
+		}
+	}
+}
+
+func TestLoadConflictPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	policy, err := LoadConflictPolicy(filepath.Join(dir, "missing.json"))
+	if err != nil || len(policy.Rules) != len(DefaultConflictPolicy().Rules) {
+		t.Errorf("a missing file should give the default policy, got %+v, %v", policy, err)
+	}
+
+	path := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(path, []byte(`{"default_severity": "soft", "min_buffer_minutes": 15}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = LoadConflictPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.DefaultSeverity != SeveritySoft || policy.MinBufferMinutes != 15 {
+		t.Errorf("got %+v", policy)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"rules": [{"categories": ["gym", "*"], "severity": "soft"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConflictPolicy(path); err == nil {
+		t.Error("a rule with an unknown category should be rejected")
+	}
+}
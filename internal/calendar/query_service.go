@@ -8,62 +8,143 @@ import (
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 )
 
-// QueryService handles calendar queries
+// QueryService handles calendar queries across one or more calendars
 type QueryService struct {
-	client *Client
+	client       *Client
+	calendarIDs  []string      // client's calendar first, then any additional calendars
+	availability *Availability   // when free time can be booked; nil means the default profile
+	policy       *ConflictPolicy // which events may overlap; nil means the default policy
 }
 
-// NewQueryService creates a new query service
-func NewQueryService(client *Client) *QueryService {
+// NewQueryService creates a new query service that aggregates events from the
+// client's calendar and any additional calendars
+func NewQueryService(client *Client, additionalCalendarIDs ...string) *QueryService {
+	calendarIDs := []string{client.GetCalendarID()}
+	for _, id := range additionalCalendarIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || containsString(calendarIDs, id) {
+			continue
+		}
+		calendarIDs = append(calendarIDs, id)
+	}
+
 	return &QueryService{
-		client: client,
+		client:      client,
+		calendarIDs: calendarIDs,
+	}
+}
+
+// WithCalendar returns a query service that reads only from the given calendar.
+// An explicitly chosen calendar replaces the configured set.
+func (qs *QueryService) WithCalendar(calendarID string) *QueryService {
+	if calendarID == "" {
+		return qs
 	}
+	return NewQueryService(qs.client.WithCalendar(calendarID)).WithAvailability(qs.availability).WithPolicy(qs.policy)
+}
+
+// WithAvailability returns a query service that finds free time within the
+// given availability profile
+func (qs *QueryService) WithAvailability(availability *Availability) *QueryService {
+	service := *qs
+	service.availability = availability
+	return &service
+}
+
+// WithPolicy returns a query service that reports conflicts by the given
+// conflict policy
+func (qs *QueryService) WithPolicy(policy *ConflictPolicy) *QueryService {
+	service := *qs
+	service.policy = policy
+	return &service
+}
+
+// GetAvailability returns the availability profile free time is found within
+func (qs *QueryService) GetAvailability() *Availability {
+	return qs.availability
+}
+
+// WithLocation returns a query service that works in the given timezone
+func (qs *QueryService) WithLocation(location *time.Location) *QueryService {
+	client := qs.client.WithLocation(location)
+	if client == qs.client {
+		return qs
+	}
+
+	service := *qs
+	service.client = client
+	return &service
+}
+
+// GetCalendarIDs returns the calendars this service aggregates
+func (qs *QueryService) GetCalendarIDs() []string {
+	return qs.calendarIDs
+}
+
+// GetClient returns the client the service reads the main calendar with
+func (qs *QueryService) GetClient() *Client {
+	return qs.client
+}
+
+// listEvents lists events from every configured calendar, tagged with their
+// source calendar and merged in start time order. Failures on additional
+// calendars are logged and skipped; a failure on the main calendar is returned.
+func (qs *QueryService) listEvents(timeMin, timeMax time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	seen := make(map[string]bool)
+
+	for i, calendarID := range qs.calendarIDs {
+		events, err := qs.client.WithCalendar(calendarID).ListEvents(timeMin, timeMax)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			fmt.Printf("⚠️ Skipping calendar %s: %v\n", calendarID, err)
+			continue
+		}
+
+		for _, event := range events {
+			// Shared events can appear in several calendars; keep the first copy
+			key := event.EventID + "|" + event.Start
+			if event.EventID != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			tasks = append(tasks, event)
+		}
+	}
+
+	if len(qs.calendarIDs) > 1 {
+		sortTasksByStart(tasks)
+	}
+	return tasks, nil
 }
 
 // GetTodaysSchedule returns today's events
 func (qs *QueryService) GetTodaysSchedule() ([]models.Task, error) {
-	return qs.client.GetTodaysEvents()
+	now := qs.client.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	tasks, err := qs.listEvents(startOfDay, endOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve events: %v", err)
+	}
+
+	return tasks, nil
 }
 
 // GetUpcomingEvents returns events for the next N days
 func (qs *QueryService) GetUpcomingEvents(days int) ([]models.Task, error) {
-	now := time.Now()
+	now := qs.client.Now()
 	startTime := now
 	endTime := now.AddDate(0, 0, days)
 
-	events, err := qs.client.service.Events.List("primary").
-		TimeMin(startTime.Format(time.RFC3339)).
-		TimeMax(endTime.Format(time.RFC3339)).
-		SingleEvents(true).
-		OrderBy("startTime").
-		MaxResults(int64(100)).
-		Do()
-
+	tasks, err := qs.listEvents(startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve upcoming events: %v", err)
 	}
 
-	var tasks []models.Task
-	for _, event := range events.Items {
-		start := event.Start.DateTime
-		if start == "" {
-			start = event.Start.Date + "T00:00:00+05:30"
-		}
-		end := event.End.DateTime
-		if end == "" {
-			end = event.End.Date + "T23:59:59+05:30"
-		}
-
-		task := models.Task{
-			Summary:     event.Summary,
-			Start:       start,
-			End:         end,
-			Description: event.Description,
-			Location:    event.Location,
-		}
-		tasks = append(tasks, task)
-	}
-
 	return tasks, nil
 }
 
@@ -90,45 +171,17 @@ func (qs *QueryService) SearchEvents(keyword string, days int) ([]models.Task, e
 
 // GetEventsByDateRange returns events within a specific date range
 func (qs *QueryService) GetEventsByDateRange(startDate, endDate time.Time) ([]models.Task, error) {
-	events, err := qs.client.service.Events.List("primary").
-		TimeMin(startDate.Format(time.RFC3339)).
-		TimeMax(endDate.Format(time.RFC3339)).
-		SingleEvents(true).
-		OrderBy("startTime").
-		MaxResults(int64(100)).
-		Do()
-
+	tasks, err := qs.listEvents(startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve events by date range: %v", err)
 	}
 
-	var tasks []models.Task
-	for _, event := range events.Items {
-		start := event.Start.DateTime
-		if start == "" {
-			start = event.Start.Date + "T00:00:00+05:30"
-		}
-		end := event.End.DateTime
-		if end == "" {
-			end = event.End.Date + "T23:59:59+05:30"
-		}
-
-		task := models.Task{
-			Summary:     event.Summary,
-			Start:       start,
-			End:         end,
-			Description: event.Description,
-			Location:    event.Location,
-		}
-		tasks = append(tasks, task)
-	}
-
 	return tasks, nil
 }
 
 // GetTomorrowsEvents returns tomorrow's events
 func (qs *QueryService) GetTomorrowsEvents() ([]models.Task, error) {
-	now := time.Now()
+	now := qs.client.Now()
 	tomorrow := now.AddDate(0, 0, 1)
 	startOfTomorrow := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
 	endOfTomorrow := startOfTomorrow.Add(24 * time.Hour)
@@ -138,7 +191,7 @@ func (qs *QueryService) GetTomorrowsEvents() ([]models.Task, error) {
 
 // GetThisWeeksEvents returns events for the current week
 func (qs *QueryService) GetThisWeeksEvents() ([]models.Task, error) {
-	now := time.Now()
+	now := qs.client.Now()
 	
 	// Calculate start of week (Monday)
 	weekday := int(now.Weekday())
@@ -156,7 +209,7 @@ func (qs *QueryService) GetThisWeeksEvents() ([]models.Task, error) {
 
 // GetNextWeeksEvents returns events for next week
 func (qs *QueryService) GetNextWeeksEvents() ([]models.Task, error) {
-	now := time.Now()
+	now := qs.client.Now()
 	
 	// Calculate start of next week
 	weekday := int(now.Weekday())
@@ -175,6 +228,20 @@ func (qs *QueryService) GetNextWeeksEvents() ([]models.Task, error) {
 
 // GetFreeTimeSlots finds free time slots in a given day
 func (qs *QueryService) GetFreeTimeSlots(date time.Time, minDuration time.Duration) ([]models.TimeSlot, error) {
+	return qs.GetFreeTimeSlotsWithBuffer(date, minDuration, 0)
+}
+
+// GetFreeTimeSlotsWithBuffer finds free time slots in a given day that keep
+// buffer clear before and after every event
+func (qs *QueryService) GetFreeTimeSlotsWithBuffer(date time.Time, minDuration time.Duration, buffer time.Duration) ([]models.TimeSlot, error) {
+	return qs.GetCommonFreeTimeSlots(date, minDuration, buffer, nil)
+}
+
+// GetCommonFreeTimeSlots finds the free time in a given day that the user
+// shares with other people, whose busy times are given as events. The
+// buffer is kept clear around everyone's events.
+func (qs *QueryService) GetCommonFreeTimeSlots(date time.Time, minDuration time.Duration, buffer time.Duration, othersBusy []models.Task) ([]models.TimeSlot, error) {
+	date = date.In(qs.client.Location())
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
@@ -182,6 +249,7 @@ func (qs *QueryService) GetFreeTimeSlots(date time.Time, minDuration time.Durati
 	if err != nil {
 		return nil, err
 	}
+	events = append(events, othersBusy...)
 
 	// Convert events to time slots
 	var busySlots []models.TimeSlot
@@ -196,8 +264,8 @@ func (qs *QueryService) GetFreeTimeSlots(date time.Time, minDuration time.Durati
 		}
 
 		busySlots = append(busySlots, models.TimeSlot{
-			Start: startTime,
-			End:   endTime,
+			Start: startTime.Add(-buffer),
+			End:   endTime.Add(buffer),
 		})
 	}
 
@@ -205,7 +273,53 @@ func (qs *QueryService) GetFreeTimeSlots(date time.Time, minDuration time.Durati
 	return qs.findFreeSlots(startOfDay, endOfDay, busySlots, minDuration), nil
 }
 
-// findFreeSlots finds free time slots between busy periods
+// GetAttendeesBusy looks up when attendees are busy between timeMin and
+// timeMax. Their busy times are returned as events titled e.g. "Priya's
+// busy time", together with the attendees that couldn't be checked: those
+// without an email and those the backend couldn't look up.
+func (qs *QueryService) GetAttendeesBusy(attendees []models.Attendee, timeMin, timeMax time.Time) ([]models.Task, []models.Attendee, error) {
+	var ids []string
+	var unchecked []models.Attendee
+	for _, attendee := range attendees {
+		if attendee.Email == "" {
+			unchecked = append(unchecked, attendee)
+			continue
+		}
+		ids = append(ids, attendee.Email)
+	}
+	if len(ids) == 0 {
+		return nil, unchecked, nil
+	}
+
+	busy, err := qs.client.FreeBusy(ids, timeMin, timeMax)
+	if err != nil {
+		return nil, attendees, err
+	}
+
+	var events []models.Task
+	location := qs.client.Location()
+	for _, attendee := range attendees {
+		if attendee.Email == "" {
+			continue
+		}
+		slots, ok := busy[attendee.Email]
+		if !ok {
+			unchecked = append(unchecked, attendee)
+			continue
+		}
+		for _, slot := range slots {
+			events = append(events, models.Task{
+				Summary: attendee.Label() + "'s busy time",
+				Start:   slot.Start.In(location).Format(time.RFC3339),
+				End:     slot.End.In(location).Format(time.RFC3339),
+			})
+		}
+	}
+	return events, unchecked, nil
+}
+
+// findFreeSlots finds free time slots between busy periods, within the
+// day's bookable time
 func (qs *QueryService) findFreeSlots(dayStart, dayEnd time.Time, busySlots []models.TimeSlot, minDuration time.Duration) []models.TimeSlot {
 	var freeSlots []models.TimeSlot
 
@@ -218,48 +332,48 @@ func (qs *QueryService) findFreeSlots(dayStart, dayEnd time.Time, busySlots []mo
 		}
 	}
 
-	// Working hours (9 AM to 6 PM)
-	workStart := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), 9, 0, 0, 0, dayStart.Location())
-	workEnd := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), 18, 0, 0, 0, dayStart.Location())
+	// Look for free time in each bookable period of the availability profile
+	for _, period := range qs.availability.Profile().Bookable(dayStart) {
+		workStart, workEnd := period.Start, period.End
+		currentTime := workStart
 
-	currentTime := workStart
+		for _, busySlot := range busySlots {
+			// Skip if busy slot is outside this period
+			if busySlot.End.Before(workStart) || busySlot.Start.After(workEnd) {
+				continue
+			}
 
-	for _, busySlot := range busySlots {
-		// Skip if busy slot is outside working hours
-		if busySlot.End.Before(workStart) || busySlot.Start.After(workEnd) {
-			continue
-		}
+			// Adjust busy slot to this period
+			slotStart := busySlot.Start
+			if slotStart.Before(workStart) {
+				slotStart = workStart
+			}
+			slotEnd := busySlot.End
+			if slotEnd.After(workEnd) {
+				slotEnd = workEnd
+			}
 
-		// Adjust busy slot to working hours
-		slotStart := busySlot.Start
-		if slotStart.Before(workStart) {
-			slotStart = workStart
-		}
-		slotEnd := busySlot.End
-		if slotEnd.After(workEnd) {
-			slotEnd = workEnd
+			// Check if there's a free slot before this busy slot
+			if currentTime.Before(slotStart) && slotStart.Sub(currentTime) >= minDuration {
+				freeSlots = append(freeSlots, models.TimeSlot{
+					Start: currentTime,
+					End:   slotStart,
+				})
+			}
+
+			// Move current time to end of busy slot
+			if slotEnd.After(currentTime) {
+				currentTime = slotEnd
+			}
 		}
 
-		// Check if there's a free slot before this busy slot
-		if currentTime.Before(slotStart) && slotStart.Sub(currentTime) >= minDuration {
+		// Check for free time after the last busy slot
+		if currentTime.Before(workEnd) && workEnd.Sub(currentTime) >= minDuration {
 			freeSlots = append(freeSlots, models.TimeSlot{
 				Start: currentTime,
-				End:   slotStart,
+				End:   workEnd,
 			})
 		}
-
-		// Move current time to end of busy slot
-		if slotEnd.After(currentTime) {
-			currentTime = slotEnd
-		}
-	}
-
-	// Check for free time after the last busy slot
-	if currentTime.Before(workEnd) && workEnd.Sub(currentTime) >= minDuration {
-		freeSlots = append(freeSlots, models.TimeSlot{
-			Start: currentTime,
-			End:   workEnd,
-		})
 	}
 
 	return freeSlots
@@ -267,7 +381,7 @@ func (qs *QueryService) findFreeSlots(dayStart, dayEnd time.Time, busySlots []mo
 
 // GetNextEvent returns the next upcoming event
 func (qs *QueryService) GetNextEvent() (*models.Task, error) {
-	now := time.Now()
+	now := qs.client.Now()
 	events, err := qs.GetUpcomingEvents(30) // Look ahead 30 days
 
 	if err != nil {
@@ -366,7 +480,8 @@ func (qs *QueryService) GetEventCount(days int) (int, error) {
 	return len(events), nil
 }
 
-// HasConflicts checks if there are any overlapping events
+// HasConflicts returns the pairs of upcoming events that conflict under the
+// conflict policy, hard or soft
 func (qs *QueryService) HasConflicts(days int) ([]models.ConflictPair, error) {
 	events, err := qs.GetUpcomingEvents(days)
 	if err != nil {
@@ -377,27 +492,28 @@ func (qs *QueryService) HasConflicts(days int) ([]models.ConflictPair, error) {
 
 	for i := 0; i < len(events); i++ {
 		for j := i + 1; j < len(events); j++ {
-			event1 := events[i]
-			event2 := events[j]
-
-			start1, err1 := time.Parse(time.RFC3339, event1.Start)
-			end1, err2 := time.Parse(time.RFC3339, event1.End)
-			start2, err3 := time.Parse(time.RFC3339, event2.Start)
-			end2, err4 := time.Parse(time.RFC3339, event2.End)
-
-			if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			result := qs.policy.Check(events[j], events[i])
+			if result.Severity == SeverityNone {
 				continue
 			}
-
-			// Check for overlap
-			if start1.Before(end2) && end1.After(start2) {
-				conflicts = append(conflicts, models.ConflictPair{
-					Event1: event1,
-					Event2: event2,
-				})
-			}
+			conflicts = append(conflicts, models.ConflictPair{
+				Event1:   events[i],
+				Event2:   events[j],
+				Severity: result.Severity,
+				Reason:   result.Reason,
+			})
 		}
 	}
 
 	return conflicts, nil
 }
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
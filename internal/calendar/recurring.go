@@ -0,0 +1,232 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// RecurrenceScope selects which occurrences of a recurring series a change applies to
+type RecurrenceScope string
+
+const (
+	// ScopeInstance changes only the selected occurrence
+	ScopeInstance RecurrenceScope = "instance"
+	// ScopeFollowing changes the selected occurrence and every later one
+	ScopeFollowing RecurrenceScope = "following"
+	// ScopeSeries changes every occurrence
+	ScopeSeries RecurrenceScope = "series"
+)
+
+// ParseRecurrenceScope parses a scope name. An empty name means ScopeInstance.
+func ParseRecurrenceScope(value string) (RecurrenceScope, error) {
+	switch RecurrenceScope(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ScopeInstance:
+		return ScopeInstance, nil
+	case ScopeFollowing:
+		return ScopeFollowing, nil
+	case ScopeSeries:
+		return ScopeSeries, nil
+	}
+	return "", fmt.Errorf("invalid scope %q: use instance, following or series", value)
+}
+
+// GetSeries retrieves the recurring series an occurrence belongs to
+func (c *Client) GetSeries(occurrence models.Task) (*models.Task, error) {
+	if occurrence.RecurringEventID == "" {
+		return nil, fmt.Errorf("event %s is not part of a recurring series", occurrence.EventID)
+	}
+
+	series, err := c.backend.GetEvent(c.calendarID, occurrence.RecurringEventID, c.location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve recurring series: %v", err)
+	}
+	if !series.IsRecurring() {
+		return nil, fmt.Errorf("event %s has no recurrence", occurrence.RecurringEventID)
+	}
+	return series, nil
+}
+
+// DeleteRecurringEvent deletes an occurrence of a recurring series, the
+// occurrence and every later one, or the whole series
+func (c *Client) DeleteRecurringEvent(occurrence models.Task, scope RecurrenceScope) error {
+	series, err := c.GetSeries(occurrence)
+	if err != nil {
+		return err
+	}
+
+	start, seriesStart, err := occurrenceTimes(occurrence, *series)
+	if err != nil {
+		return err
+	}
+
+	// Cutting a series at its first occurrence removes all of it
+	if scope == ScopeFollowing && !seriesStart.Before(start) {
+		scope = ScopeSeries
+	}
+
+	switch scope {
+	case ScopeSeries:
+		return c.DeleteEvent(series.EventID)
+	case ScopeFollowing:
+		recurrence := endRecurrenceBefore(series.Recurrence, start, occurrence.AllDay)
+		if err := c.setRecurrence(*series, recurrence, nil); err != nil {
+			return fmt.Errorf("failed to end recurring series: %w", err)
+		}
+	default:
+		recurrence := excludeOccurrence(series.Recurrence, start, occurrence.AllDay)
+		if err := c.setRecurrence(*series, recurrence, nil); err != nil {
+			return fmt.Errorf("failed to skip occurrence: %w", err)
+		}
+	}
+
+	fmt.Printf("🗑️ Recurring event deleted (%s): %s\n", scope, occurrence.EventID)
+	return nil
+}
+
+// UpdateRecurringEvent applies updated, the new version of an occurrence, to
+// that occurrence only, to it and every later one, or to the whole series.
+// Moving the occurrence moves the affected occurrences by the same amount.
+//
+// A single occurrence is split off into its own event, and "following"
+// starts a new series, so the returned event may have a new ID.
+func (c *Client) UpdateRecurringEvent(occurrence, updated models.Task, scope RecurrenceScope) (*models.Task, error) {
+	series, err := c.GetSeries(occurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	start, seriesStart, err := occurrenceTimes(occurrence, *series)
+	if err != nil {
+		return nil, err
+	}
+	newTimes, err := updated.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+	shift := newTimes.StartTime.Sub(start)
+	duration := newTimes.EndTime.Sub(newTimes.StartTime)
+
+	if scope == ScopeFollowing && !seriesStart.Before(start) {
+		scope = ScopeSeries
+	}
+
+	switch scope {
+	case ScopeSeries:
+		changed := *series
+		changed.Summary = updated.Summary
+		changed.Description = updated.Description
+		changed.Location = updated.Location
+		changed.SendUpdates = updated.SendUpdates
+		if len(updated.Attendees) > 0 {
+			changed.Attendees = updated.Attendees
+		}
+		if updated.Conference != nil {
+			changed.Conference = updated.Conference
+		}
+		if len(updated.Reminders) > 0 {
+			changed.Reminders = updated.Reminders
+		}
+		changed.Start = seriesStart.Add(shift).Format(time.RFC3339)
+		changed.End = seriesStart.Add(shift).Add(duration).Format(time.RFC3339)
+		return c.UpdateEvent(changed)
+
+	case ScopeFollowing:
+		recurrence, err := continueRecurrence(*series, start, shift)
+		if err != nil {
+			return nil, err
+		}
+
+		next := standaloneCopy(updated)
+		next.Recurrence = recurrence
+		created, err := c.insert(next)
+		if err != nil {
+			return nil, err
+		}
+
+		ended := endRecurrenceBefore(series.Recurrence, start, occurrence.AllDay)
+		if err := c.setRecurrence(*series, ended, created); err != nil {
+			return nil, c.discard(*created, fmt.Errorf("failed to end recurring series: %w", err))
+		}
+		return created, nil
+
+	default:
+		created, err := c.insert(standaloneCopy(updated))
+		if err != nil {
+			return nil, err
+		}
+
+		skipped := excludeOccurrence(series.Recurrence, start, occurrence.AllDay)
+		if err := c.setRecurrence(*series, skipped, created); err != nil {
+			return nil, c.discard(*created, fmt.Errorf("failed to skip occurrence: %w", err))
+		}
+		return created, nil
+	}
+}
+
+// insert creates an event and returns it as stored, in the client's timezone.
+// It isn't journaled until the series it is split off from has changed.
+func (c *Client) insert(task models.Task) (*models.Task, error) {
+	if task.TimeZone == "" {
+		task.TimeZone = c.timeZoneName()
+	}
+
+	created, err := c.backend.InsertEvent(c.calendarID, task, c.location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %v", err)
+	}
+
+	created.CalendarID = c.calendarID
+	created.Start = c.inLocation(created.Start)
+	created.End = c.inLocation(created.End)
+	return created, nil
+}
+
+// setRecurrence replaces the recurrence of a series and journals the change.
+// split, if set, is an event split off from the series; it is journaled only
+// once the series has changed, as one change with it.
+func (c *Client) setRecurrence(series models.Task, recurrence []string, split *models.Task) error {
+	updated, err := c.backend.UpdateEvent(c.calendarID, models.Task{EventID: series.EventID, Recurrence: recurrence}, c.location)
+	if err != nil {
+		return err
+	}
+	var entries []JournalEntry
+	if split != nil {
+		entries = append(entries, c.entry(OperationCreate, *split, nil))
+	}
+	entries = append(entries, c.entry(OperationUpdate, *updated, &series))
+	c.recordAll(entries...)
+	return nil
+}
+
+// discard removes an event split off a series whose change failed. It was
+// never journaled, so it is deleted from the backend directly.
+func (c *Client) discard(created models.Task, cause error) error {
+	if err := c.backend.DeleteEvent(c.calendarID, created.EventID); err != nil {
+		return fmt.Errorf("%w; %s was left in the calendar: %v", cause, created.EventID, err)
+	}
+	return cause
+}
+
+// standaloneCopy strips the identity of an occurrence so it can be stored as a new event
+func standaloneCopy(task models.Task) models.Task {
+	task.EventID = ""
+	task.RecurringEventID = ""
+	task.Recurrence = nil
+	return task
+}
+
+// occurrenceTimes parses the start of an occurrence and of its series
+func occurrenceTimes(occurrence, series models.Task) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, occurrence.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid occurrence start: %v", err)
+	}
+	seriesStart, err := time.Parse(time.RFC3339, series.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid series start: %v", err)
+	}
+	return start, seriesStart, nil
+}
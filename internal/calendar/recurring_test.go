@@ -0,0 +1,137 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// newSeriesClient returns a client on a memory backend holding a daily
+// series of five 9:00 UTC check-ins from 2026-10-01
+func newSeriesClient(t *testing.T) (*Client, []models.Task) {
+	t.Helper()
+	client := NewClient(NewMemoryBackend(), "").WithLocation(time.UTC)
+	err := client.CreateEvent(models.Task{
+		Summary:    "Check-in",
+		Start:      "2026-10-01T09:00:00Z",
+		End:        "2026-10-01T09:30:00Z",
+		Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	occurrences, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 5 {
+		t.Fatalf("got %d occurrences, want 5", len(occurrences))
+	}
+	return client, occurrences
+}
+
+func TestDeleteRecurringEvent(t *testing.T) {
+	tests := []struct {
+		scope RecurrenceScope
+		want  []string
+	}{
+		{ScopeInstance, []string{"2026-10-01T09:00:00Z", "2026-10-02T09:00:00Z", "2026-10-04T09:00:00Z", "2026-10-05T09:00:00Z"}},
+		{ScopeFollowing, []string{"2026-10-01T09:00:00Z", "2026-10-02T09:00:00Z"}},
+		{ScopeSeries, nil},
+	}
+
+	for _, test := range tests {
+		client, occurrences := newSeriesClient(t)
+		if err := client.DeleteRecurringEvent(occurrences[2], test.scope); err != nil {
+			t.Errorf("%s: %v", test.scope, err)
+			continue
+		}
+
+		left, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := occurrenceStarts(left); !equalStrings(got, test.want) {
+			t.Errorf("%s: left %v, want %v", test.scope, got, test.want)
+		}
+	}
+}
+
+func TestUpdateRecurringEvent(t *testing.T) {
+	tests := []struct {
+		scope RecurrenceScope
+		want  []string
+	}{
+		{ScopeInstance, []string{"Check-in 09:00", "Check-in 09:00", "Retro 10:00", "Check-in 09:00", "Check-in 09:00"}},
+		{ScopeFollowing, []string{"Check-in 09:00", "Check-in 09:00", "Retro 10:00", "Retro 10:00", "Retro 10:00"}},
+		{ScopeSeries, []string{"Retro 10:00", "Retro 10:00", "Retro 10:00", "Retro 10:00", "Retro 10:00"}},
+	}
+
+	for _, test := range tests {
+		client, occurrences := newSeriesClient(t)
+		updated := occurrences[2]
+		updated.Summary = "Retro"
+		updated.Start = "2026-10-03T10:00:00Z"
+		updated.End = "2026-10-03T10:30:00Z"
+
+		if _, err := client.UpdateRecurringEvent(occurrences[2], updated, test.scope); err != nil {
+			t.Errorf("%s: %v", test.scope, err)
+			continue
+		}
+
+		events, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, event := range events {
+			start, _ := time.Parse(time.RFC3339, event.Start)
+			got = append(got, event.Summary+" "+start.Format("15:04"))
+		}
+		if !equalStrings(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.scope, got, test.want)
+		}
+	}
+}
+
+func TestUpdateRecurringSeriesDetails(t *testing.T) {
+	client, occurrences := newSeriesClient(t)
+	updated := occurrences[2]
+	updated.Attendees = []models.Attendee{{Email: "sam@example.com", ResponseStatus: models.ResponseNeedsAction}}
+	updated.Reminders = []models.Reminder{{Method: models.ReminderPopup, Minutes: 5}}
+	updated.Conference = &models.Conference{Link: "https://meet.example.com/check-in"}
+
+	if _, err := client.UpdateRecurringEvent(occurrences[2], updated, ScopeSeries); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if len(event.Attendees) != 1 || event.Attendees[0].Email != "sam@example.com" {
+			t.Errorf("%s: attendees %+v", event.Start, event.Attendees)
+		}
+		if len(event.Reminders) != 1 || event.Reminders[0].Minutes != 5 {
+			t.Errorf("%s: reminders %+v", event.Start, event.Reminders)
+		}
+		if event.Conference == nil || event.Conference.Link != "https://meet.example.com/check-in" {
+			t.Errorf("%s: conference %+v", event.Start, event.Conference)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
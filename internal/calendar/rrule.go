@@ -0,0 +1,515 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// maxOccurrences bounds the number of occurrences returned by one expansion
+const maxOccurrences = 1000
+
+// recurrenceRule is a parsed RFC 5545 RRULE
+type recurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []weekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// weekdayNum is a BYDAY entry such as MO, 2TU or -1FR
+type weekdayNum struct {
+	N       int // 0 means every such weekday in the period
+	Weekday time.Weekday
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRRule parses the value of an RRULE line, with or without the "RRULE:" prefix
+func parseRRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	rule := &recurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid RRULE part: %q", part)
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			switch val {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.Freq = val
+			default:
+				return nil, fmt.Errorf("unsupported RRULE frequency: %s", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE interval: %s", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid RRULE count: %s", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, allDay, err := parseICalTime(icalProperty{Value: val, Params: map[string]string{}}, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE until: %s", val)
+			}
+			if allDay {
+				// A date-only UNTIL includes the whole day
+				until = until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				if len(day) < 2 {
+					return nil, fmt.Errorf("invalid RRULE weekday: %s", day)
+				}
+				weekday, ok := icalWeekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid RRULE weekday: %s", day)
+				}
+				n := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					parsed, err := strconv.Atoi(prefix)
+					if err != nil {
+						return nil, fmt.Errorf("invalid RRULE weekday: %s", day)
+					}
+					n = parsed
+				}
+				rule.ByDay = append(rule.ByDay, weekdayNum{N: n, Weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid RRULE month day: %s", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(month)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid RRULE month: %s", month)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			// Weeks always start on Monday here
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part: %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("RRULE has no FREQ")
+	}
+	return rule, nil
+}
+
+// ValidateRecurrence checks that a task's recurrence lines can be expanded
+func ValidateRecurrence(task models.Task) error {
+	_, _, err := parseRecurrence(task.Recurrence, time.UTC)
+	return err
+}
+
+// parseRecurrence splits recurrence lines into the rule and excluded start times
+func parseRecurrence(lines []string, loc *time.Location) (*recurrenceRule, map[int64]bool, error) {
+	var rule *recurrenceRule
+	excluded := make(map[int64]bool)
+
+	for _, line := range lines {
+		prop, err := parseICalLine(strings.TrimSpace(line))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch prop.Name {
+		case "RRULE":
+			if rule != nil {
+				return nil, nil, fmt.Errorf("only one RRULE is supported")
+			}
+			if rule, err = parseRRule(prop.Value, loc); err != nil {
+				return nil, nil, err
+			}
+		case "EXDATE":
+			for _, value := range strings.Split(prop.Value, ",") {
+				exdate, _, err := parseICalTime(icalProperty{Value: value, Params: prop.Params}, loc)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid EXDATE: %s", value)
+				}
+				excluded[exdate.Unix()] = true
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported recurrence line: %s", prop.Name)
+		}
+	}
+
+	if rule == nil {
+		return nil, nil, fmt.Errorf("recurrence has no RRULE")
+	}
+	return rule, excluded, nil
+}
+
+// ExpandRecurrence returns the occurrences of a recurring task that overlap
+// [timeMin, timeMax). A task without recurrence is returned as-is if it overlaps.
+// Occurrences of a stored series get instance IDs that point back to it.
+func ExpandRecurrence(task models.Task, timeMin, timeMax time.Time) ([]models.Task, error) {
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.IsRecurring() {
+		if parsed.StartTime.Before(timeMax) && parsed.EndTime.After(timeMin) {
+			return []models.Task{task}, nil
+		}
+		return nil, nil
+	}
+
+	// Repeat on the wall clock of the event's own timezone so occurrences
+	// keep their local time across DST changes
+	loc := parsed.StartTime.Location()
+	if task.TimeZone != "" {
+		if tzLoc, err := time.LoadLocation(task.TimeZone); err == nil {
+			loc = tzLoc
+		}
+	}
+	start := parsed.StartTime.In(loc)
+	duration := parsed.EndTime.Sub(parsed.StartTime)
+
+	rule, excluded, err := parseRecurrence(task.Recurrence, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []models.Task
+	generated := 0
+	for period := 0; len(occurrences) < maxOccurrences; period++ {
+		candidates := rule.periodStarts(start, period)
+		if candidates == nil {
+			break
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Before(start) {
+				continue
+			}
+			if (!rule.Until.IsZero() && candidate.After(rule.Until)) ||
+				(rule.Count > 0 && generated >= rule.Count) ||
+				!candidate.Before(timeMax) {
+				return occurrences, nil
+			}
+			generated++
+
+			if excluded[candidate.Unix()] {
+				continue
+			}
+			end := candidate.Add(duration)
+			if end.After(timeMin) {
+				occurrence := task
+				occurrence.Start = candidate.Format(time.RFC3339)
+				occurrence.End = end.Format(time.RFC3339)
+				occurrence.Recurrence = nil
+				if task.EventID != "" {
+					occurrence.EventID = InstanceEventID(task.EventID, candidate, task.AllDay)
+					occurrence.RecurringEventID = task.EventID
+				}
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	return occurrences, nil
+}
+
+// InstanceEventID returns the ID of the occurrence of a series starting at start
+func InstanceEventID(seriesID string, start time.Time, allDay bool) string {
+	if allDay {
+		return seriesID + "_" + start.Format("20060102")
+	}
+	return seriesID + "_" + start.UTC().Format("20060102T150405Z")
+}
+
+// splitInstanceEventID splits an ID made by InstanceEventID into the ID of
+// the series and the start of the occurrence; an all-day start is a date in
+// loc. ok is false for any other ID.
+func splitInstanceEventID(eventID string, loc *time.Location) (seriesID string, start time.Time, allDay bool, ok bool) {
+	separator := strings.LastIndex(eventID, "_")
+	if separator <= 0 {
+		return "", time.Time{}, false, false
+	}
+
+	stamp := eventID[separator+1:]
+	if start, err := time.Parse("20060102T150405Z", stamp); err == nil {
+		return eventID[:separator], start, false, true
+	}
+	if start, err := time.ParseInLocation("20060102", stamp, loc); err == nil {
+		return eventID[:separator], start, true, true
+	}
+	return "", time.Time{}, false, false
+}
+
+// findOccurrence returns the occurrence of series with the instance ID
+// eventID, which starts at start
+func findOccurrence(series models.Task, eventID string, start time.Time) (*models.Task, error) {
+	occurrences, err := ExpandRecurrence(series, start.Add(-24*time.Hour), start.Add(48*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.EventID == eventID {
+			return &occurrence, nil
+		}
+	}
+	return nil, fmt.Errorf("event %s not found", eventID)
+}
+
+// excludeOccurrence adds an EXDATE for the occurrence starting at start
+func excludeOccurrence(recurrence []string, start time.Time, allDay bool) []string {
+	result := append([]string{}, recurrence...)
+	if allDay {
+		return append(result, "EXDATE;VALUE=DATE:"+start.Format("20060102"))
+	}
+	return append(result, "EXDATE:"+start.UTC().Format("20060102T150405Z"))
+}
+
+// endRecurrenceBefore ends the rule before the occurrence starting at start
+func endRecurrenceBefore(recurrence []string, start time.Time, allDay bool) []string {
+	until := start.Add(-time.Second).UTC().Format("20060102T150405Z")
+	if allDay {
+		until = start.AddDate(0, 0, -1).Format("20060102")
+	}
+
+	var result []string
+	for _, line := range recurrence {
+		if isRRuleLine(line) {
+			line = rewriteRRule(line, map[string]string{"UNTIL": until}, "COUNT")
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// continueRecurrence returns the recurrence for a new series that takes over
+// a series from the occurrence at from, with every occurrence moved by shift.
+// A COUNT is reduced by the occurrences before from; exclusions before from
+// are dropped and later ones are moved along.
+func continueRecurrence(series models.Task, from time.Time, shift time.Duration) ([]string, error) {
+	parsed, err := series.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+	rule, _, err := parseRecurrence(series.Recurrence, parsed.StartTime.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, line := range series.Recurrence {
+		if isRRuleLine(line) {
+			if rule.Count > 0 {
+				// Count the earlier occurrences, including excluded ones
+				counting := series
+				counting.Recurrence = []string{line}
+				counting.EventID = ""
+				before, err := ExpandRecurrence(counting, parsed.StartTime, from)
+				if err != nil {
+					return nil, err
+				}
+				remaining := rule.Count - len(before)
+				if remaining < 1 {
+					return nil, fmt.Errorf("no occurrences left after %s", from.Format(time.RFC3339))
+				}
+				line = rewriteRRule(line, map[string]string{"COUNT": strconv.Itoa(remaining)})
+			}
+			result = append(result, line)
+			continue
+		}
+
+		prop, err := parseICalLine(strings.TrimSpace(line))
+		if err != nil || prop.Name != "EXDATE" {
+			result = append(result, line)
+			continue
+		}
+		for _, value := range strings.Split(prop.Value, ",") {
+			exdate, allDay, err := parseICalTime(icalProperty{Value: value, Params: prop.Params}, parsed.StartTime.Location())
+			if err != nil || exdate.Before(from) {
+				continue
+			}
+			result = excludeOccurrence(result, exdate.Add(shift), allDay)
+		}
+	}
+	return result, nil
+}
+
+// isRRuleLine reports whether a recurrence line is an RRULE
+func isRRuleLine(line string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line)), "RRULE:")
+}
+
+// rewriteRRule sets and removes parts of an RRULE line
+func rewriteRRule(line string, set map[string]string, remove ...string) string {
+	value := strings.TrimSpace(line)[len("RRULE:"):]
+
+	var parts []string
+	for _, part := range strings.Split(value, ";") {
+		key := strings.ToUpper(strings.SplitN(part, "=", 2)[0])
+		if _, replaced := set[key]; replaced || containsString(remove, key) {
+			continue
+		}
+		parts = append(parts, part)
+	}
+
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"="+set[key])
+	}
+
+	return "RRULE:" + strings.Join(parts, ";")
+}
+
+// periodStarts returns the sorted candidate start times in the given period
+// (day, week, month or year) after the first. It returns nil once the rule
+// has been stepped too far, which ends rules that never match.
+func (r *recurrenceRule) periodStarts(start time.Time, period int) []time.Time {
+	step := period * r.Interval
+	if step > 100000 {
+		return nil
+	}
+
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	candidates := []time.Time{}
+	switch r.Freq {
+	case "DAILY":
+		day := at(start.Year(), start.Month(), start.Day()+step)
+		if r.matchesDay(day) && r.matchesMonth(day) {
+			candidates = append(candidates, day)
+		}
+	case "WEEKLY":
+		// Weeks start on Monday
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset+7*step)
+		if len(r.ByDay) == 0 {
+			candidates = append(candidates, at(monday.Year(), monday.Month(), monday.Day()+offset))
+		}
+		for _, day := range r.ByDay {
+			candidates = append(candidates, at(monday.Year(), monday.Month(), monday.Day()+(int(day.Weekday)+6)%7))
+		}
+	case "MONTHLY":
+		first := at(start.Year(), start.Month()+time.Month(step), 1)
+		if r.matchesMonth(first) {
+			candidates = r.monthStarts(first, start.Day(), at)
+		}
+	case "YEARLY":
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			candidates = append(candidates, r.monthStarts(at(start.Year()+step, month, 1), start.Day(), at)...)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// monthStarts returns the candidate days within the month beginning at first
+func (r *recurrenceRule) monthStarts(first time.Time, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	year, month := first.Year(), first.Month()
+	daysInMonth := at(year, month+1, 0).Day()
+
+	candidates := []time.Time{}
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = daysInMonth + day + 1
+			}
+			if day >= 1 && day <= daysInMonth {
+				candidates = append(candidates, at(year, month, day))
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, byDay := range r.ByDay {
+			var matches []time.Time
+			for day := 1; day <= daysInMonth; day++ {
+				if date := at(year, month, day); date.Weekday() == byDay.Weekday {
+					matches = append(matches, date)
+				}
+			}
+			switch {
+			case byDay.N == 0:
+				candidates = append(candidates, matches...)
+			case byDay.N > 0 && byDay.N <= len(matches):
+				candidates = append(candidates, matches[byDay.N-1])
+			case byDay.N < 0 && -byDay.N <= len(matches):
+				candidates = append(candidates, matches[len(matches)+byDay.N])
+			}
+		}
+	default:
+		// Months without the start's day (e.g. the 31st) are skipped
+		if defaultDay <= daysInMonth {
+			candidates = append(candidates, at(year, month, defaultDay))
+		}
+	}
+
+	return candidates
+}
+
+// matchesDay reports whether a daily candidate satisfies BYDAY
+func (r *recurrenceRule) matchesDay(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonth reports whether a candidate satisfies BYMONTH
+func (r *recurrenceRule) matchesMonth(date time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, month := range r.ByMonth {
+		if month == date.Month() {
+			return true
+		}
+	}
+	return false
+}
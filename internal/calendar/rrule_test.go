@@ -0,0 +1,280 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+func TestParseRRule(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		value string
+		want  recurrenceRule
+	}{
+		{"RRULE:FREQ=DAILY", recurrenceRule{Freq: "DAILY", Interval: 1}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR;WKST=MO", recurrenceRule{Freq: "WEEKLY", Interval: 2, ByDay: []weekdayNum{
+			{Weekday: time.Monday}, {Weekday: time.Wednesday}, {Weekday: time.Friday},
+		}}},
+		{"FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=6", recurrenceRule{Freq: "MONTHLY", Interval: 1, Count: 6, ByDay: []weekdayNum{
+			{N: 2, Weekday: time.Tuesday}, {N: -1, Weekday: time.Friday},
+		}}},
+		{"FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=1,-1", recurrenceRule{Freq: "YEARLY", Interval: 1, ByMonth: []time.Month{time.March, time.September}, ByMonthDay: []int{1, -1}}},
+		{"FREQ=DAILY;UNTIL=20261031T120000Z", recurrenceRule{Freq: "DAILY", Interval: 1, Until: time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)}},
+		{"FREQ=DAILY;UNTIL=20261031", recurrenceRule{Freq: "DAILY", Interval: 1, Until: time.Date(2026, 10, 31, 23, 59, 59, 0, newYork)}},
+	}
+	for _, test := range tests {
+		got, err := parseRRule(test.value, newYork)
+		if err != nil {
+			t.Errorf("%s: %v", test.value, err)
+			continue
+		}
+		if !got.Until.Equal(test.want.Until) {
+			t.Errorf("%s: until %s, want %s", test.value, got.Until, test.want.Until)
+		}
+		got.Until, test.want.Until = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.value, *got, test.want)
+		}
+	}
+
+	for _, value := range []string{
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=x",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ",
+	} {
+		if _, err := parseRRule(value, time.UTC); err == nil {
+			t.Errorf("%s: expected an error", value)
+		}
+	}
+}
+
+func TestExpandRecurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      string
+		timeZone   string
+		recurrence []string
+		timeMin    time.Time
+		timeMax    time.Time
+		want       []string
+	}{
+		{
+			name:       "weekdays",
+			start:      "2026-10-16T09:00:00Z", // a Friday
+			recurrence: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR"},
+			timeMin:    date(2026, 10, 16),
+			timeMax:    date(2026, 10, 24),
+			want:       []string{"2026-10-16T09:00:00Z", "2026-10-19T09:00:00Z", "2026-10-21T09:00:00Z", "2026-10-23T09:00:00Z"},
+		},
+		{
+			name:       "count includes occurrences before the window",
+			start:      "2026-10-01T09:00:00Z",
+			recurrence: []string{"RRULE:FREQ=DAILY;COUNT=5"},
+			timeMin:    date(2026, 10, 4),
+			timeMax:    date(2026, 11, 1),
+			want:       []string{"2026-10-04T09:00:00Z", "2026-10-05T09:00:00Z"},
+		},
+		{
+			name:       "until is inclusive",
+			start:      "2026-10-01T09:00:00Z",
+			recurrence: []string{"RRULE:FREQ=WEEKLY;UNTIL=20261015T090000Z"},
+			timeMin:    date(2026, 10, 1),
+			timeMax:    date(2026, 11, 1),
+			want:       []string{"2026-10-01T09:00:00Z", "2026-10-08T09:00:00Z", "2026-10-15T09:00:00Z"},
+		},
+		{
+			name:       "exdate skips an occurrence but still counts it",
+			start:      "2026-10-01T09:00:00Z",
+			recurrence: []string{"RRULE:FREQ=DAILY;COUNT=3", "EXDATE:20261002T090000Z"},
+			timeMin:    date(2026, 10, 1),
+			timeMax:    date(2026, 11, 1),
+			want:       []string{"2026-10-01T09:00:00Z", "2026-10-03T09:00:00Z"},
+		},
+		{
+			name:       "last friday of the month",
+			start:      "2026-10-30T17:00:00Z",
+			recurrence: []string{"RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3"},
+			timeMin:    date(2026, 10, 1),
+			timeMax:    date(2027, 6, 1),
+			want:       []string{"2026-10-30T17:00:00Z", "2026-11-27T17:00:00Z", "2026-12-25T17:00:00Z"},
+		},
+		{
+			name:       "keeps the local time across the end of DST",
+			start:      "2026-10-30T09:00:00-04:00",
+			timeZone:   "America/New_York",
+			recurrence: []string{"RRULE:FREQ=DAILY;COUNT=4"},
+			timeMin:    date(2026, 10, 1),
+			timeMax:    date(2026, 11, 30),
+			want:       []string{"2026-10-30T09:00:00-04:00", "2026-10-31T09:00:00-04:00", "2026-11-01T09:00:00-05:00", "2026-11-02T09:00:00-05:00"},
+		},
+		{
+			name:       "exdate in UTC matches a local occurrence",
+			start:      "2026-10-30T09:00:00-04:00",
+			timeZone:   "America/New_York",
+			recurrence: []string{"RRULE:FREQ=DAILY;COUNT=4", "EXDATE:20261101T140000Z"},
+			timeMin:    date(2026, 10, 1),
+			timeMax:    date(2026, 11, 30),
+			want:       []string{"2026-10-30T09:00:00-04:00", "2026-10-31T09:00:00-04:00", "2026-11-02T09:00:00-05:00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, test.start)
+			task := models.Task{
+				EventID:    "series",
+				Summary:    "Check-in",
+				Start:      test.start,
+				End:        start.Add(30 * time.Minute).Format(time.RFC3339),
+				TimeZone:   test.timeZone,
+				Recurrence: test.recurrence,
+			}
+
+			occurrences, err := ExpandRecurrence(task, test.timeMin, test.timeMax)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := occurrenceStarts(occurrences); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			for _, occurrence := range occurrences {
+				if occurrence.RecurringEventID != "series" || !strings.HasPrefix(occurrence.EventID, "series_") || occurrence.IsRecurring() {
+					t.Errorf("occurrence %+v should point back to its series", occurrence)
+				}
+			}
+		})
+	}
+}
+
+func TestInstanceEventID(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		start  time.Time
+		allDay bool
+		id     string
+	}{
+		{time.Date(2026, 11, 2, 9, 0, 0, 0, newYork), false, "series_20261102T140000Z"},
+		{time.Date(2026, 11, 2, 0, 0, 0, 0, newYork), true, "series_20261102"},
+	}
+	for _, test := range tests {
+		id := InstanceEventID("series", test.start, test.allDay)
+		if id != test.id {
+			t.Errorf("got %s, want %s", id, test.id)
+		}
+
+		seriesID, start, allDay, ok := splitInstanceEventID(id, newYork)
+		if !ok || seriesID != "series" || !start.Equal(test.start) || allDay != test.allDay {
+			t.Errorf("%s: split into %s, %s, %v, %v", id, seriesID, start, allDay, ok)
+		}
+	}
+
+	for _, id := range []string{"series", "my_event", "_20261102"} {
+		if _, _, _, ok := splitInstanceEventID(id, time.UTC); ok {
+			t.Errorf("%s should not be an instance ID", id)
+		}
+	}
+}
+
+func TestExcludeOccurrence(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	recurrence := []string{"RRULE:FREQ=DAILY"}
+
+	got := excludeOccurrence(recurrence, time.Date(2026, 11, 2, 9, 0, 0, 0, newYork), false)
+	if want := []string{"RRULE:FREQ=DAILY", "EXDATE:20261102T140000Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got = excludeOccurrence(recurrence, time.Date(2026, 11, 2, 0, 0, 0, 0, newYork), true)
+	if want := []string{"RRULE:FREQ=DAILY", "EXDATE;VALUE=DATE:20261102"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(recurrence) != 1 {
+		t.Errorf("the original recurrence was changed: %v", recurrence)
+	}
+}
+
+func TestEndRecurrenceBefore(t *testing.T) {
+	recurrence := []string{"RRULE:FREQ=DAILY;COUNT=10", "EXDATE:20261002T090000Z"}
+
+	got := endRecurrenceBefore(recurrence, time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC), false)
+	if want := []string{"RRULE:FREQ=DAILY;UNTIL=20261005T085959Z", "EXDATE:20261002T090000Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got = endRecurrenceBefore([]string{"RRULE:FREQ=WEEKLY;UNTIL=20261231"}, date(2026, 10, 5), true)
+	if want := []string{"RRULE:FREQ=WEEKLY;UNTIL=20261004"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestContinueRecurrence(t *testing.T) {
+	series := models.Task{
+		EventID:    "series",
+		Start:      "2026-10-01T09:00:00Z",
+		End:        "2026-10-01T09:30:00Z",
+		Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=10", "EXDATE:20261002T090000Z,20261007T090000Z"},
+	}
+	from := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+
+	got, err := continueRecurrence(series, from, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"RRULE:FREQ=DAILY;COUNT=6", "EXDATE:20261007T100000Z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The old and new series together keep the original occurrences
+	ended := series
+	ended.Recurrence = endRecurrenceBefore(series.Recurrence, from, false)
+	continued := series
+	continued.Start = from.Format(time.RFC3339)
+	continued.End = from.Add(30 * time.Minute).Format(time.RFC3339)
+	if continued.Recurrence, err = continueRecurrence(series, from, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var starts []string
+	for _, task := range []models.Task{ended, continued} {
+		occurrences, err := ExpandRecurrence(task, date(2026, 10, 1), date(2026, 11, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		starts = append(starts, occurrenceStarts(occurrences)...)
+	}
+	whole, _ := ExpandRecurrence(series, date(2026, 10, 1), date(2026, 11, 1))
+	if want := occurrenceStarts(whole); !reflect.DeepEqual(starts, want) {
+		t.Errorf("split series has %v, want %v", starts, want)
+	}
+
+	series.Recurrence = []string{"RRULE:FREQ=DAILY;COUNT=3"}
+	if _, err := continueRecurrence(series, from, 0); err == nil {
+		t.Error("continuing after the last occurrence should fail")
+	}
+}
+
+func occurrenceStarts(tasks []models.Task) []string {
+	var starts []string
+	for _, task := range tasks {
+		starts = append(starts, task.Start)
+	}
+	return starts
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s is not available: %v", name, err)
+	}
+	return loc
+}
@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"fmt"
+)
+
+// Undo reverses the last n changes recorded in the journal, newest first,
+// and returns the entries it undid; a change made of several entries, such
+// as an occurrence split off a series, is undone as a whole. It stops at
+// the first entry that cannot be reversed.
+func (c *Client) Undo(n int) ([]JournalEntry, error) {
+	if c.journal == nil {
+		return nil, fmt.Errorf("undo journal is not enabled")
+	}
+
+	var undone []JournalEntry
+	for _, entry := range c.journal.Recent(n) {
+		if err := c.WithCalendar(entry.CalendarID).reverse(entry); err != nil {
+			return undone, fmt.Errorf("failed to undo %s of %s: %v", entry.Operation, describeEntry(entry), err)
+		}
+		if err := c.journal.MarkUndone(entry.ID); err != nil {
+			return undone, err
+		}
+
+		fmt.Printf("↩️ Undid %s of %s\n", entry.Operation, describeEntry(entry))
+		undone = append(undone, entry)
+	}
+
+	return undone, nil
+}
+
+// reverse applies the opposite of a journal entry directly to the backend,
+// so undoing is not itself recorded
+func (c *Client) reverse(entry JournalEntry) error {
+	switch entry.Operation {
+	case OperationCreate:
+		return c.backend.DeleteEvent(c.calendarID, entry.Event.EventID)
+
+	case OperationDelete:
+		if entry.Event.Start == "" {
+			return fmt.Errorf("the deleted event's details were not recorded")
+		}
+		restored := entry.Event
+		restored.EventID = ""
+		restored.RecurringEventID = ""
+		_, err := c.backend.InsertEvent(c.calendarID, restored, c.location)
+		return err
+
+	case OperationUpdate:
+		if entry.Previous == nil {
+			return fmt.Errorf("the previous version was not recorded")
+		}
+		// Replacing rather than patching clears fields the change added
+		_, err := c.backend.ReplaceEvent(c.calendarID, *entry.Previous, c.location)
+		return err
+	}
+
+	return fmt.Errorf("unknown operation %q", entry.Operation)
+}
+
+// describeEntry names the event a journal entry is about
+func describeEntry(entry JournalEntry) string {
+	if entry.Event.Summary != "" {
+		return entry.Event.Summary
+	}
+	return entry.Event.EventID
+}
@@ -0,0 +1,103 @@
+package calendar
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// newJournaledClient returns a client on a memory backend that journals its
+// changes in a temporary file
+func newJournaledClient(t *testing.T) *Client {
+	t.Helper()
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(NewMemoryBackend(), "").WithLocation(time.UTC).WithJournal(journal)
+}
+
+func TestUndoSplitOccurrence(t *testing.T) {
+	for _, scope := range []RecurrenceScope{ScopeInstance, ScopeFollowing} {
+		client := newJournaledClient(t)
+		err := client.CreateEvent(models.Task{
+			Summary:    "Check-in",
+			Start:      "2026-10-01T09:00:00Z",
+			End:        "2026-10-01T09:30:00Z",
+			Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=5"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		occurrences, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updated := occurrences[2]
+		updated.Summary = "Retro"
+		if _, err := client.UpdateRecurringEvent(occurrences[2], updated, scope); err != nil {
+			t.Fatal(err)
+		}
+
+		undone, err := client.Undo(1)
+		if err != nil {
+			t.Fatalf("%s: %v", scope, err)
+		}
+		if len(undone) != 2 || undone[0].ChangeID() != undone[1].ChangeID() {
+			t.Errorf("%s: undid %+v, want the series update and the split-off event as one change", scope, undone)
+		}
+
+		events, err := client.ListEvents(date(2026, 10, 1), date(2026, 11, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range events {
+			if event.Summary != "Check-in" || event.RecurringEventID == "" {
+				t.Errorf("%s: %s %q is left after undo", scope, event.Start, event.Summary)
+			}
+		}
+		if len(events) != 5 {
+			t.Errorf("%s: got %d events after undo, want the 5 check-ins", scope, len(events))
+		}
+
+		// The series creation is the next change back
+		if recent := client.GetJournal().Recent(1); len(recent) != 1 || recent[0].Operation != OperationCreate {
+			t.Errorf("%s: next change to undo is %+v", scope, recent)
+		}
+	}
+}
+
+func TestUndoClearsAddedFields(t *testing.T) {
+	client := newJournaledClient(t)
+	if err := client.CreateEvent(models.Task{Summary: "Interview", Start: "2026-10-20T10:00:00Z", End: "2026-10-20T11:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := client.ListEvents(date(2026, 10, 20), date(2026, 10, 21))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("got %d events, %v", len(events), err)
+	}
+
+	change := models.Task{
+		EventID:   events[0].EventID,
+		Location:  "Room 4",
+		Attendees: []models.Attendee{{Email: "sam@example.com"}},
+		Reminders: []models.Reminder{{Method: models.ReminderPopup, Minutes: 10}},
+	}
+	if _, err := client.UpdateEvent(change); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Undo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := client.GetEvent(events[0].EventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Location != "" || len(restored.Attendees) != 0 || len(restored.Reminders) != 0 {
+		t.Errorf("undo should clear what the update added, got %+v", restored)
+	}
+}
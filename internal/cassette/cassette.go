@@ -0,0 +1,180 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cassette modes
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// Interaction kinds
+const (
+	KindLLM      = "llm"
+	KindCalendar = "calendar"
+)
+
+// Interaction is one recorded call and its outcome
+type Interaction struct {
+	Kind     string          `json:"kind"`   // "llm" or "calendar"
+	Method   string          `json:"method"` // e.g. "GeneratePlan" or "ListEvents"
+	Time     time.Time       `json:"time"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Decode unmarshals the recorded response into response and returns the
+// recorded error, if any
+func (i *Interaction) Decode(response interface{}) error {
+	if len(i.Response) > 0 && response != nil {
+		if err := json.Unmarshal(i.Response, response); err != nil {
+			return fmt.Errorf("unable to decode recorded %s response: %v", i.Method, err)
+		}
+	}
+	if i.Error != "" {
+		return errors.New(i.Error)
+	}
+	return nil
+}
+
+// DecodeRequest unmarshals the recorded request into request
+func (i *Interaction) DecodeRequest(request interface{}) error {
+	return json.Unmarshal(i.Request, request)
+}
+
+// Cassette is a file of recorded LLM and calendar interactions. In record
+// mode every call is appended and saved; in replay mode calls are served
+// back in the order they were recorded, per kind and method.
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	mode         string
+	interactions []Interaction
+	next         map[string]int // replay position per kind and method
+}
+
+var (
+	openMu sync.Mutex
+	opened = map[string]*Cassette{}
+)
+
+// Open returns the cassette at path in the given mode. A recording starts
+// empty and replaces the file; a replay loads it. Every caller in the process
+// shares one cassette per path, so LLM and calendar calls interleave in a
+// single file in the order they happened.
+func Open(path, mode string) (*Cassette, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("invalid cassette mode %q: use record or replay", mode)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("cassette path is not set")
+	}
+
+	openMu.Lock()
+	defer openMu.Unlock()
+
+	if cassette, ok := opened[path]; ok {
+		if cassette.mode != mode {
+			return nil, fmt.Errorf("cassette %s is already open for %s", path, cassette.mode)
+		}
+		return cassette, nil
+	}
+
+	cassette := &Cassette{path: path, mode: mode, next: map[string]int{}}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cassette: %v", err)
+		}
+		if err := json.Unmarshal(data, &cassette.interactions); err != nil {
+			return nil, fmt.Errorf("unable to parse cassette %s: %v", path, err)
+		}
+	}
+
+	opened[path] = cassette
+	return cassette, nil
+}
+
+// Mode returns ModeRecord or ModeReplay
+func (c *Cassette) Mode() string {
+	return c.mode
+}
+
+// Path returns the cassette file
+func (c *Cassette) Path() string {
+	return c.path
+}
+
+// Replaying reports whether calls should be served from the cassette
+func (c *Cassette) Replaying() bool {
+	return c.mode == ModeReplay
+}
+
+// Record appends an interaction and saves the cassette
+func (c *Cassette) Record(kind, method string, request, response interface{}, callErr error) error {
+	interaction := Interaction{Kind: kind, Method: method, Time: time.Now()}
+
+	var err error
+	if interaction.Request, err = json.Marshal(request); err != nil {
+		return fmt.Errorf("unable to record %s request: %v", method, err)
+	}
+	if callErr != nil {
+		interaction.Error = callErr.Error()
+	} else if response != nil {
+		if interaction.Response, err = json.Marshal(response); err != nil {
+			return fmt.Errorf("unable to record %s response: %v", method, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+	return c.save()
+}
+
+// Next returns the next recorded interaction of the given kind and method
+func (c *Cassette) Next(kind, method string) (*Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := kind + "/" + method
+	for i := c.next[key]; i < len(c.interactions); i++ {
+		if c.interactions[i].Kind == kind && c.interactions[i].Method == method {
+			c.next[key] = i + 1
+			interaction := c.interactions[i]
+			return &interaction, nil
+		}
+	}
+	c.next[key] = len(c.interactions)
+	return nil, fmt.Errorf("cassette %s has no more recorded %s %s calls", c.path, kind, method)
+}
+
+// save writes the cassette atomically. The caller must hold c.mu.
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(c.path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("unable to create cassette directory: %v", err)
+		}
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write cassette: %v", err)
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,250 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// clockFormat is the layout of the times in an availability profile
+const clockFormat = "15:04"
+
+// holidayLookahead is how far ahead Describe lists holidays
+const holidayLookahead = 14 * 24 * time.Hour
+
+// AvailabilityProfile describes when the user can be booked. Free-time
+// searches, conflict resolution and the AI prompts keep to it.
+type AvailabilityProfile struct {
+	// Working hours keyed by lowercase weekday, e.g. "monday"; days without
+	// an entry are not worked
+	WorkingHours    map[string]TimeRange `json:"working_hours"`
+	Lunch           *TimeRange           `json:"lunch,omitempty"` // kept free on working days
+	NoMeetingBlocks []NoMeetingBlock     `json:"no_meeting_blocks,omitempty"`
+	Holidays        []Holiday            `json:"holidays,omitempty"`
+}
+
+// TimeRange is a range of time of day in 24-hour HH:MM
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// NoMeetingBlock is a time of day kept free of meetings
+type NoMeetingBlock struct {
+	Name string   `json:"name,omitempty"`
+	Days []string `json:"days,omitempty"` // lowercase weekdays; empty means every day
+	TimeRange
+}
+
+// Holiday is a day off
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name,omitempty"`
+}
+
+// DefaultAvailabilityProfile returns working hours of 9 AM to 6 PM every day
+func DefaultAvailabilityProfile() AvailabilityProfile {
+	profile := AvailabilityProfile{WorkingHours: make(map[string]TimeRange)}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		profile.WorkingHours[weekdayKey(day)] = TimeRange{Start: "09:00", End: "18:00"}
+	}
+	return profile
+}
+
+// Validate checks the profile's weekdays, times and dates
+func (p AvailabilityProfile) Validate() error {
+	for day, hours := range p.WorkingHours {
+		if !isWeekdayKey(day) {
+			return fmt.Errorf("unknown weekday %q in working hours, expected e.g. monday", day)
+		}
+		if err := hours.validate(); err != nil {
+			return fmt.Errorf("working hours on %s: %v", day, err)
+		}
+	}
+	if p.Lunch != nil {
+		if err := p.Lunch.validate(); err != nil {
+			return fmt.Errorf("lunch: %v", err)
+		}
+	}
+	for i, block := range p.NoMeetingBlocks {
+		for _, day := range block.Days {
+			if !isWeekdayKey(day) {
+				return fmt.Errorf("unknown weekday %q in no-meeting block %d, expected e.g. monday", day, i+1)
+			}
+		}
+		if err := block.validate(); err != nil {
+			return fmt.Errorf("no-meeting block %d: %v", i+1, err)
+		}
+	}
+	for _, holiday := range p.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return fmt.Errorf("invalid holiday date %q, expected YYYY-MM-DD", holiday.Date)
+		}
+	}
+	return nil
+}
+
+// Bookable returns the times on date's day that can be booked: the working
+// hours without lunch and no-meeting blocks. Holidays and days without
+// working hours have none. Times are in date's timezone.
+func (p AvailabilityProfile) Bookable(date time.Time) []TimeSlot {
+	if _, ok := p.HolidayOn(date); ok {
+		return nil
+	}
+	hours, ok := p.WorkingHours[weekdayKey(date.Weekday())]
+	if !ok {
+		return nil
+	}
+	start, end, err := hours.On(date)
+	if err != nil {
+		return nil
+	}
+
+	bookable := []TimeSlot{{Start: start, End: end}}
+	if p.Lunch != nil {
+		if start, end, err := p.Lunch.On(date); err == nil {
+			bookable = subtractSlot(bookable, start, end)
+		}
+	}
+	for _, block := range p.NoMeetingBlocks {
+		if !block.appliesOn(date.Weekday()) {
+			continue
+		}
+		if start, end, err := block.On(date); err == nil {
+			bookable = subtractSlot(bookable, start, end)
+		}
+	}
+	return bookable
+}
+
+// HolidayOn returns the holiday on date's day, if any
+func (p AvailabilityProfile) HolidayOn(date time.Time) (Holiday, bool) {
+	day := date.Format("2006-01-02")
+	for _, holiday := range p.Holidays {
+		if holiday.Date == day {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Describe summarizes the profile for an AI prompt, listing the holidays in
+// the two weeks from now
+func (p AvailabilityProfile) Describe(now time.Time) string {
+	var days []string
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7) // Monday first
+		name := day.String()[:3]
+		if hours, ok := p.WorkingHours[weekdayKey(day)]; ok {
+			days = append(days, fmt.Sprintf("%s %s-%s", name, hours.Start, hours.End))
+		} else {
+			days = append(days, name+" off")
+		}
+	}
+	lines := []string{"Working hours: " + strings.Join(days, ", ")}
+
+	if p.Lunch != nil {
+		lines = append(lines, fmt.Sprintf("Lunch: %s-%s, keep it free", p.Lunch.Start, p.Lunch.End))
+	}
+	for _, block := range p.NoMeetingBlocks {
+		when := "every day"
+		if len(block.Days) > 0 {
+			when = strings.Join(block.Days, ", ")
+		}
+		name := block.Name
+		if name == "" {
+			name = "No meetings"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s-%s on %s, keep it free", name, block.Start, block.End, when))
+	}
+
+	var holidays []string
+	today := now.Format("2006-01-02")
+	until := now.Add(holidayLookahead).Format("2006-01-02")
+	for _, holiday := range p.Holidays {
+		if holiday.Date >= today && holiday.Date <= until {
+			holidays = append(holidays, strings.TrimSpace(holiday.Date+" "+holiday.Name))
+		}
+	}
+	if len(holidays) > 0 {
+		sort.Strings(holidays)
+		lines = append(lines, "Holidays (nothing can be booked): "+strings.Join(holidays, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// On returns the range on date's day, in date's timezone
+func (r TimeRange) On(date time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse(clockFormat, r.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start %q, expected HH:MM", r.Start)
+	}
+	end, err := time.Parse(clockFormat, r.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end %q, expected HH:MM", r.End)
+	}
+
+	at := func(clock time.Time) time.Time {
+		return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, date.Location())
+	}
+	return at(start), at(end), nil
+}
+
+// validate checks that the range has valid times and ends after it starts
+func (r TimeRange) validate() error {
+	start, end, err := r.On(time.Now())
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%s must be after %s", r.End, r.Start)
+	}
+	return nil
+}
+
+// appliesOn checks whether the block is kept free on a weekday
+func (b NoMeetingBlock) appliesOn(day time.Weekday) bool {
+	if len(b.Days) == 0 {
+		return true
+	}
+	for _, name := range b.Days {
+		if name == weekdayKey(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractSlot removes the time between start and end from slots
+func subtractSlot(slots []TimeSlot, start, end time.Time) []TimeSlot {
+	var remaining []TimeSlot
+	for _, slot := range slots {
+		if !start.Before(slot.End) || !end.After(slot.Start) {
+			remaining = append(remaining, slot)
+			continue
+		}
+		if start.After(slot.Start) {
+			remaining = append(remaining, TimeSlot{Start: slot.Start, End: start})
+		}
+		if end.Before(slot.End) {
+			remaining = append(remaining, TimeSlot{Start: end, End: slot.End})
+		}
+	}
+	return remaining
+}
+
+// weekdayKey is the profile's name for a weekday, e.g. "monday"
+func weekdayKey(day time.Weekday) string {
+	return strings.ToLower(day.String())
+}
+
+// isWeekdayKey checks whether name is a weekday as the profile names them
+func isWeekdayKey(name string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if name == weekdayKey(day) {
+			return true
+		}
+	}
+	return false
+}
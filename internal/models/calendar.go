@@ -0,0 +1,10 @@
+package models
+
+// CalendarInfo describes a calendar the user can read from or write to
+type CalendarInfo struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Primary    bool   `json:"primary,omitempty"`
+	AccessRole string `json:"access_role,omitempty"` // "owner", "writer", "reader", ...
+	TimeZone   string `json:"timezone,omitempty"`
+}
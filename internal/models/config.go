@@ -5,16 +5,71 @@ type Config struct {
 	AI       AIConfig       `json:"ai"`
 	Calendar CalendarConfig `json:"calendar"`
 	Google   GoogleConfig   `json:"google"`
+	CalDAV   CalDAVConfig   `json:"caldav"`
 }
 
 // AIConfig holds AI service configuration
 type AIConfig struct {
-	GitHubToken      string `json:"github_token"`
+	GitHubToken string `json:"github_token"`
+	// Providers tried in order: "github", "openai", "anthropic", "ollama", "fake".
+	// Empty tries GitHub, OpenAI and Anthropic, whichever have an API key.
+	Providers []string          `json:"providers,omitempty"`
+	GitHub    LLMProviderConfig `json:"github"`
+	OpenAI    LLMProviderConfig `json:"openai"`
+	Anthropic LLMProviderConfig `json:"anthropic"`
+	Ollama    LLMProviderConfig `json:"ollama"` // also any llama.cpp server
+	// Fixture of scripted responses for the offline "fake" provider
+	FakeFixturePath string `json:"fake_fixture_path,omitempty"`
+	// Records or replays every plan request
+	Cassette CassetteConfig `json:"cassette"`
+}
+
+// CassetteConfig selects record/replay of LLM and calendar traffic
+type CassetteConfig struct {
+	Mode string `json:"mode,omitempty"` // "record", "replay" or empty to disable
+	Path string `json:"path,omitempty"` // cassette file, shared by LLM and calendar calls
+}
+
+// LLMProviderConfig holds the settings of one LLM provider
+type LLMProviderConfig struct {
+	APIKey  string   `json:"api_key,omitempty"`
+	BaseURL string   `json:"base_url,omitempty"` // empty uses the provider's default endpoint
+	Models  []string `json:"models,omitempty"`   // tried in order; empty uses the provider's defaults
 }
 
 // CalendarConfig holds calendar configuration
 type CalendarConfig struct {
-	TimeZone string `json:"timezone"`
+	TimeZone   string `json:"timezone"`
+	Backend    string `json:"backend"`     // "google", "caldav" or "memory"
+	CalendarID string `json:"calendar_id"` // calendar events are read from and written to
+	// Additional calendars (work, personal, shared team calendars) whose
+	// events are included in views, free-time and conflict checks
+	AdditionalCalendarIDs []string `json:"additional_calendar_ids,omitempty"`
+	// File recording assistant-made changes so they can be undone; empty disables undo
+	JournalPath string `json:"journal_path,omitempty"`
+	// Records or replays every backend call
+	Cassette CassetteConfig `json:"cassette"`
+	// File holding the availability profile (working hours, lunch,
+	// no-meeting blocks, holidays); empty keeps the default in memory
+	AvailabilityPath string `json:"availability_path,omitempty"`
+	// File holding the conflict policy (which events may overlap, buffers,
+	// travel time); empty or missing uses the default policy
+	ConflictPolicyPath string `json:"conflict_policy_path,omitempty"`
+	// Emails of people named in requests, keyed by lowercase name, so their
+	// free/busy can be looked up
+	Contacts map[string]string `json:"contacts,omitempty"`
+	// How attendees hear about new and changed events: "all",
+	// "externalOnly" or "none"
+	SendUpdates string `json:"send_updates,omitempty"`
+	// Where the assistant puts events it finds a time for itself
+	SlotPreferences SlotPreferences `json:"slot_preferences"`
+}
+
+// SlotPreferences guide the search for a free slot
+type SlotPreferences struct {
+	BufferMinutes  int    `json:"buffer_minutes"`            // kept free before and after other events
+	PreferredStart string `json:"preferred_start,omitempty"` // HH:MM; slots inside the preferred hours rank first
+	PreferredEnd   string `json:"preferred_end,omitempty"`   // HH:MM
 }
 
 // GoogleConfig holds Google OAuth configuration
@@ -25,4 +80,12 @@ type GoogleConfig struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	TokenExpiry  string `json:"token_expiry"`
-}
\ No newline at end of file
+}
+
+// CalDAVConfig holds CalDAV server configuration
+type CalDAVConfig struct {
+	URL      string `json:"url"` // calendar home, e.g. https://cloud.example.com/remote.php/dav/calendars/alice/
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Calendar string `json:"calendar"` // default calendar name used for "primary"
+}
@@ -4,8 +4,19 @@ import "time"
 
 // QueryRequest represents a user's calendar query
 type QueryRequest struct {
-	Question string `json:"question"`
-	TimeZone string `json:"timezone"`
+	Question    string `json:"question"`
+	TimeZone    string `json:"timezone"`
+	CalendarID  string `json:"calendar_id,omitempty"`  // overrides the configured calendar
+	Scope       string `json:"scope,omitempty"`        // instance, following or series for recurring events
+	DryRun      bool   `json:"dry_run,omitempty"`      // preview create and delete without changing the calendar
+	AutoResolve bool   `json:"auto_resolve,omitempty"` // book a conflicting new event at its best alternative time
+	SendUpdates string `json:"send_updates,omitempty"` // all, externalOnly or none; how attendees hear about changes
+	Stream      bool   `json:"stream,omitempty"`       // send progress as Server-Sent Events
+	SessionID   string `json:"session_id,omitempty"`   // continues a conversation
+
+	ConfirmationToken  string `json:"confirmation_token,omitempty"`  // executes a planned delete
+	ClarificationToken string `json:"clarification_token,omitempty"` // answers a clarifying question
+	Choice             string `json:"choice,omitempty"`              // the ID of the chosen option
 }
 
 // QueryResponse represents the response to a calendar query
@@ -13,8 +24,10 @@ type QueryResponse struct {
 	Answer  string `json:"answer"`
 	Events  []Task `json:"events,omitempty"`
 	Success bool   `json:"success"`
-	Action  string `json:"action,omitempty"` // "create", "view", "delete"
+	Action  string `json:"action,omitempty"` // "create", "view", "update", "delete" or "clarify"
 	Error   string `json:"error,omitempty"`
+
+	Clarification *Clarification `json:"clarification,omitempty"` // set when Action is "clarify"
 }
 
 // QueryContext provides context for answering queries
@@ -23,6 +36,39 @@ type QueryContext struct {
 	TodaysEvents   []Task    `json:"todays_events"`
 	UpcomingEvents []Task    `json:"upcoming_events"`
 	TimeZone       string    `json:"timezone"`
+
+	Availability *AvailabilityProfile `json:"availability,omitempty"` // when the user can be booked
+	FreeToday    []TimeSlot           `json:"free_today,omitempty"`   // rest of today's free time within the availability
+	Conversation *Conversation        `json:"conversation,omitempty"` // earlier turns of a session
+}
+
+// ConversationTurn is one message of a conversation with the assistant
+type ConversationTurn struct {
+	Role    string    `json:"role"` // "user" or "assistant"
+	Content string    `json:"content"`
+	Time    time.Time `json:"time"`
+}
+
+// Clarification is a question the assistant asked before it could act
+type Clarification struct {
+	Question string                `json:"question"`          // what the assistant asked
+	Request  string                `json:"request"`           // the request it needs clarified
+	Options  []ClarificationOption `json:"options,omitempty"` // answers the user can choose between
+	Token    string                `json:"token,omitempty"`   // send back with a choice to resume the request
+}
+
+// ClarificationOption is one answer to a clarifying question
+type ClarificationOption struct {
+	ID    string `json:"id"` // sent back as the choice
+	Label string `json:"label"`
+	Event *Task  `json:"event,omitempty"` // an existing event, or a suggested new one
+}
+
+// Conversation is what a session remembers of earlier requests
+type Conversation struct {
+	History          []ConversationTurn `json:"history,omitempty"`
+	ReferencedEvents []Task             `json:"referenced_events,omitempty"` // events the last answer was about
+	Clarification    *Clarification     `json:"clarification,omitempty"`     // an unanswered question, if any
 }
 
 // TimeSlot represents a time slot
@@ -31,8 +77,21 @@ type TimeSlot struct {
 	End   time.Time `json:"end"`
 }
 
+// ScheduleConflict is a new event that conflicts with existing ones. Hard
+// conflicts come with free times it could move to; soft ones are booked anyway.
+type ScheduleConflict struct {
+	Event         Task   `json:"event"`          // the requested event
+	ConflictsWith []Task `json:"conflicts_with"` // the existing events it conflicts with
+	Severity      string `json:"severity"`       // "soft" or "hard"
+	Reason        string `json:"reason"`
+	Alternatives  []Task `json:"alternatives,omitempty"` // best first
+	ResolvedTo    *Task  `json:"resolved_to,omitempty"`  // the alternative it was booked at, with auto_resolve
+}
+
 // ConflictPair represents two conflicting events
 type ConflictPair struct {
-	Event1 Task `json:"event1"`
-	Event2 Task `json:"event2"`
+	Event1   Task   `json:"event1"`
+	Event2   Task   `json:"event2"`
+	Severity string `json:"severity"` // "soft" or "hard"
+	Reason   string `json:"reason"`   // how Event2 relates to Event1, e.g. "overlaps"
 }
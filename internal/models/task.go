@@ -13,6 +13,84 @@ End         string `json:"end"`
 EventID     string `json:"event_id,omitempty"`
 Description string `json:"description,omitempty"`
 Location    string `json:"location,omitempty"`
+AllDay      bool   `json:"all_day,omitempty"`
+CalendarID  string `json:"calendar_id,omitempty"` // source calendar
+TimeZone    string `json:"timezone,omitempty"`    // IANA timezone of start and end
+Recurrence  []string `json:"recurrence,omitempty"` // RRULE/EXDATE lines; start and end are the first occurrence
+RecurringEventID string `json:"recurring_event_id,omitempty"` // series this occurrence belongs to
+Attendees   []Attendee `json:"attendees,omitempty"` // people the event is with, besides the user
+Conference  *Conference `json:"conference,omitempty"` // video call
+SendUpdates string `json:"send_updates,omitempty"` // who is emailed about this change: "all", "externalOnly" or "none"
+Reminders   []Reminder `json:"reminders,omitempty"` // replace the calendar's default reminders
+}
+
+// Ways of telling attendees about a change
+const (
+	SendUpdatesAll      = "all"
+	SendUpdatesExternal = "externalOnly" // only people outside the user's organization
+	SendUpdatesNone     = "none"
+)
+
+// Attendee responses to an invitation
+const (
+	ResponseNeedsAction = "needsAction"
+	ResponseAccepted    = "accepted"
+	ResponseTentative   = "tentative"
+	ResponseDeclined    = "declined"
+)
+
+// Attendee is a person an event is with. People are looked up and invited
+// by email; an attendee known only by name can't be.
+type Attendee struct {
+	Email          string `json:"email,omitempty"`
+	Name           string `json:"name,omitempty"`
+	ResponseStatus string `json:"response_status,omitempty"` // e.g. "accepted"; see the Response constants
+}
+
+// Conference is an event's video call
+type Conference struct {
+	Create bool   `json:"create,omitempty"` // ask the calendar to add a call, e.g. Google Meet
+	Link   string `json:"link,omitempty"`   // join link
+}
+
+// Ways of reminding the user of an event
+const (
+	ReminderPopup = "popup"
+	ReminderEmail = "email"
+)
+
+// MaxReminderMinutes is how far ahead a reminder can be, four weeks
+const MaxReminderMinutes = 4 * 7 * 24 * 60
+
+// Reminder is a notification some time before an event starts
+type Reminder struct {
+	Method  string `json:"method"`  // "popup" or "email"
+	Minutes int    `json:"minutes"` // before the start
+}
+
+// Validate checks a reminder's method and time
+func (r Reminder) Validate() error {
+	if r.Method != ReminderPopup && r.Method != ReminderEmail {
+		return fmt.Errorf("reminder method must be %s or %s, not %q", ReminderPopup, ReminderEmail, r.Method)
+	}
+	if r.Minutes < 0 || r.Minutes > MaxReminderMinutes {
+		return fmt.Errorf("reminder must be between 0 and %d minutes before the event", MaxReminderMinutes)
+	}
+	return nil
+}
+
+// ValidSendUpdates checks a way of telling attendees about a change; empty
+// uses the default
+func ValidSendUpdates(mode string) bool {
+	return mode == "" || mode == SendUpdatesAll || mode == SendUpdatesExternal || mode == SendUpdatesNone
+}
+
+// Label returns the attendee's name, or their email if the name is unknown
+func (a Attendee) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Email
 }
 
 // ParsedTask represents a task with parsed time
@@ -39,9 +117,19 @@ func (t *Task) ValidateTask() error {
 	if t.End == "" {
 		return fmt.Errorf("task end time is required")
 	}
+	for _, reminder := range t.Reminders {
+		if err := reminder.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// IsRecurring reports whether the task repeats
+func (t *Task) IsRecurring() bool {
+	return len(t.Recurrence) > 0
+}
+
 // ParseTime converts string time to time.Time
 func (t *Task) ParseTime() (*ParsedTask, error) {
 	startTime, err := time.Parse(time.RFC3339, t.Start)
@@ -83,7 +171,7 @@ func (t *Task) IsToday() bool {
 		return false
 	}
 
-	now := time.Now()
+	now := time.Now().In(startTime.Location())
 	return startTime.Year() == now.Year() && 
 		   startTime.Month() == now.Month() && 
 		   startTime.Day() == now.Day()
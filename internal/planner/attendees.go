@@ -0,0 +1,47 @@
+package planner
+
+import (
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// AddAttendees fills in who planned events are with. A single event the AI
+// gave no attendees gets the people named in the request, e.g. "a meeting
+// with Priya and Alex". Attendees without an email get the one in the
+// contacts, if any, and names that are emails become the email.
+func (es *EnhancedScheduler) AddAttendees(tasks []models.Task, question string) []models.Task {
+	withAttendees := append([]models.Task{}, tasks...)
+	if len(withAttendees) == 1 && len(withAttendees[0].Attendees) == 0 {
+		for _, name := range es.GetIntentClassifier().ClassifyWithRules(question).Slots.Participants {
+			withAttendees[0].Attendees = append(withAttendees[0].Attendees, models.Attendee{Name: name})
+		}
+	}
+
+	for i, task := range withAttendees {
+		if len(task.Attendees) == 0 {
+			continue
+		}
+		attendees := make([]models.Attendee, len(task.Attendees))
+		for j, attendee := range task.Attendees {
+			attendees[j] = es.resolveAttendee(attendee)
+		}
+		withAttendees[i].Attendees = attendees
+	}
+	return withAttendees
+}
+
+// resolveAttendee looks up an attendee's email by name
+func (es *EnhancedScheduler) resolveAttendee(attendee models.Attendee) models.Attendee {
+	if attendee.Email != "" {
+		return attendee
+	}
+	name := strings.TrimSpace(attendee.Name)
+	if strings.Contains(name, "@") {
+		return models.Attendee{Email: name}
+	}
+	if email, ok := es.contacts[strings.ToLower(name)]; ok {
+		attendee.Email = email
+	}
+	return attendee
+}
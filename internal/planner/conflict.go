@@ -1,93 +1,72 @@
 package planner
 
 import (
-	"strings"
 	"time"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 )
 
+// alternativeTimeStep is how far apart the start times findAlternativeTime tries are
+const alternativeTimeStep = 30 * time.Minute
+
 // ConflictChecker handles time conflict detection
-type ConflictChecker struct{}
+type ConflictChecker struct {
+	availability *calendar.Availability   // when alternatives may be booked
+	policy       *calendar.ConflictPolicy // which events may overlap
+}
 
-// NewConflictChecker creates a new conflict checker
-func NewConflictChecker() *ConflictChecker {
-	return &ConflictChecker{}
+// EventConflict is an existing event a new task conflicts with
+type EventConflict struct {
+	Event    models.Task
+	Severity string // calendar.SeveritySoft or calendar.SeverityHard
+	Reason   string // e.g. "overlaps"
 }
 
-// HasTimeConflict checks if a new task conflicts with existing tasks
+// NewConflictChecker creates a new conflict checker that judges conflicts by
+// the given policy and moves conflicting tasks within the given availability
+// profile
+func NewConflictChecker(availability *calendar.Availability, policy *calendar.ConflictPolicy) *ConflictChecker {
+	return &ConflictChecker{availability: availability, policy: policy}
+}
+
+// GetPolicy returns the policy conflicts are judged by
+func (c *ConflictChecker) GetPolicy() *calendar.ConflictPolicy {
+	return c.policy
+}
+
+// HasTimeConflict checks if a new task has a hard conflict with existing tasks
 func (c *ConflictChecker) HasTimeConflict(newTask models.Task, existingTasks []models.Task) bool {
-	newStart, err1 := time.Parse(time.RFC3339, newTask.Start)
-	newEnd, err2 := time.Parse(time.RFC3339, newTask.End)
-	
-	if err1 != nil || err2 != nil {
-		return false // If we can't parse, assume no conflict
-	}
-	
-	for _, existing := range existingTasks {
-		existingStart, err1 := time.Parse(time.RFC3339, existing.Start)
-		existingEnd, err2 := time.Parse(time.RFC3339, existing.End)
-		
-		if err1 != nil || err2 != nil {
-			continue
-		}
-		
-		// Special handling for lunch breaks during work hours
-		if c.isLunchBreakDuringWork(newTask, existing) {
-			continue // Allow lunch breaks during work hours
-		}
-		
-		// Check for overlap
-		if c.timesOverlap(newStart, newEnd, existingStart, existingEnd) {
-			return true
-		}
-	}
-	
-	return false
+	return len(c.ConflictingEvents(newTask, existingTasks)) > 0
 }
 
-// isLunchBreakDuringWork checks if this is a lunch break during work hours
-func (c *ConflictChecker) isLunchBreakDuringWork(newTask models.Task, existingTask models.Task) bool {
-	// Check if new task is a lunch/break and existing is work
-	newSummary := strings.ToLower(newTask.Summary)
-	existingSummary := strings.ToLower(existingTask.Summary)
-	
-	isLunchBreak := strings.Contains(newSummary, "lunch") || 
-					strings.Contains(newSummary, "break") ||
-					strings.Contains(newSummary, "meal")
-	
-	isWorkEvent := strings.Contains(existingSummary, "work") ||
-				   strings.Contains(existingSummary, "office") ||
-				   strings.Contains(existingSummary, "job")
-	
-	if !isLunchBreak || !isWorkEvent {
-		return false
-	}
-	
-	// Check if lunch break is within work hours
-	newStart, err1 := time.Parse(time.RFC3339, newTask.Start)
-	newEnd, err2 := time.Parse(time.RFC3339, newTask.End)
-	existingStart, err3 := time.Parse(time.RFC3339, existingTask.Start)
-	existingEnd, err4 := time.Parse(time.RFC3339, existingTask.End)
-	
-	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
-		return false
+// ConflictingEvents returns the existing tasks that a new task has a hard
+// conflict with
+func (c *ConflictChecker) ConflictingEvents(newTask models.Task, existingTasks []models.Task) []models.Task {
+	var conflicts []models.Task
+	for _, conflict := range c.Conflicts(newTask, existingTasks) {
+		if conflict.Severity == calendar.SeverityHard {
+			conflicts = append(conflicts, conflict.Event)
+		}
 	}
-	
-	// Allow lunch break if it's within work hours and reasonable duration (< 2 hours)
-	duration := newEnd.Sub(newStart)
-	withinWorkHours := newStart.After(existingStart) && newEnd.Before(existingEnd)
-	reasonableDuration := duration <= 2*time.Hour
-	
-	return withinWorkHours && reasonableDuration
+	return conflicts
 }
 
-// timesOverlap checks if two time ranges overlap
-func (c *ConflictChecker) timesOverlap(start1, end1, start2, end2 time.Time) bool {
-	return start1.Before(end2) && end1.After(start2)
+// Conflicts returns every existing task a new task conflicts with, hard or soft
+func (c *ConflictChecker) Conflicts(newTask models.Task, existingTasks []models.Task) []EventConflict {
+	var conflicts []EventConflict
+	for _, existing := range existingTasks {
+		result := c.policy.Check(newTask, existing)
+		if result.Severity == calendar.SeverityNone {
+			continue
+		}
+		conflicts = append(conflicts, EventConflict{Event: existing, Severity: result.Severity, Reason: result.Reason})
+	}
+	return conflicts
 }
 
-// FindConflicts returns all conflicting tasks
+// FindConflicts returns the new tasks that have a hard conflict with
+// existing tasks
 func (c *ConflictChecker) FindConflicts(newTasks []models.Task, existingTasks []models.Task) []models.Task {
 	var conflicts []models.Task
 	
@@ -132,20 +111,19 @@ func (c *ConflictChecker) findAlternativeTime(task models.Task, existingTasks []
 	
 	duration := endTime.Sub(startTime)
 	
-	// Try different time slots throughout the day
-	for hour := 6; hour <= 22; hour++ {
-		newStart := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 
-			hour, 0, 0, 0, startTime.Location())
-		newEnd := newStart.Add(duration)
-		
-		adjustedTask := models.Task{
-			Summary: task.Summary,
-			Start:   newStart.Format(time.RFC3339),
-			End:     newEnd.Format(time.RFC3339),
-		}
-		
-		if !c.HasTimeConflict(adjustedTask, existingTasks) {
-			return adjustedTask, true
+	// Try time slots throughout the day's bookable time
+	for _, period := range c.availability.Profile().Bookable(startTime) {
+		for newStart := period.Start; !newStart.Add(duration).After(period.End); newStart = newStart.Add(alternativeTimeStep) {
+			newEnd := newStart.Add(duration)
+			
+			// Keep the location and all-day flag the policy looks at
+			adjustedTask := task
+			adjustedTask.Start = newStart.Format(time.RFC3339)
+			adjustedTask.End = newEnd.Format(time.RFC3339)
+			
+			if !c.HasTimeConflict(adjustedTask, existingTasks) {
+				return adjustedTask, true
+			}
 		}
 	}
 	
@@ -0,0 +1,432 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/ai"
+	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
+)
+
+// Intents of a unified query
+const (
+	IntentCreate = "create"
+	IntentView   = "view"
+	IntentUpdate = "update"
+	IntentDelete = "delete"
+	IntentUndo   = "undo"
+)
+
+// Sources of a classification
+const (
+	SourceRules = "rules"
+	SourceAI    = "ai"
+)
+
+// RuleConfidenceThreshold is the confidence at which the keyword rules are
+// trusted; below it the AI classifies the request
+const RuleConfidenceThreshold = 0.8
+
+// IntentSlots are the details extracted from a request
+type IntentSlots struct {
+	Title        string   `json:"title,omitempty"`
+	Date         string   `json:"date,omitempty"`     // YYYY-MM-DD
+	Time         string   `json:"time,omitempty"`     // HH:MM, 24-hour
+	Duration     string   `json:"duration,omitempty"` // e.g. "30m" or "1h30m"
+	Participants []string `json:"participants,omitempty"`
+	Reminder     string   `json:"reminder,omitempty"` // how long before the event, e.g. "30m"
+}
+
+// IntentResult is the classified intent of a request
+type IntentResult struct {
+	Intent     string      `json:"intent"`
+	Confidence float64     `json:"confidence"` // 0 to 1
+	Slots      IntentSlots `json:"slots"`
+	Source     string      `json:"source"` // "rules" or "ai"
+}
+
+// intentKeyword is a phrase that signals an intent. Weak phrases, like "get"
+// or "check", also appear in requests of other intents. Command phrases,
+// like "clear" or "plan", only signal their intent when they open the
+// request; elsewhere they are as likely to mean something else.
+type intentKeyword struct {
+	intent  string
+	phrase  string
+	weak    bool
+	command bool
+	pattern *regexp.Regexp
+}
+
+// intentPriority breaks ties between intents with the same score; an update
+// is checked before a create because "reschedule" is also about scheduling
+var intentPriority = []string{IntentUndo, IntentUpdate, IntentCreate, IntentDelete, IntentView}
+
+var intentKeywords = buildIntentKeywords(map[string][]string{
+	IntentUndo: {"undo", "revert", "roll back", "rollback", "take that back", "restore"},
+	IntentUpdate: {"move", "reschedule", "rename", "postpone", "push back", "push my", "push it",
+		"bring forward", "shift", "change", "update", "edit", "modify", "delay", "extend", "shorten",
+		"longer", "shorter"},
+	IntentDelete: {"delete", "remove", "cancel", "erase", "get rid of", "eliminate", "destroy",
+		"wipe", "purge", "skip"},
+	IntentCreate: {"schedule", "book", "add", "create", "set up", "arrange", "organize", "put",
+		"block", "remind me", "new event", "new meeting", "fit in", "pencil in", "find time",
+		"make time", "set aside", "carve out", "get me a", "get me an"},
+	IntentView: {"what's", "whats", "what is", "what are", "what do i have", "show", "display",
+		"list", "when is", "when's", "when are", "when am i", "my schedule", "my calendar",
+		"do i have", "am i free", "am i busy", "free time", "how many", "how busy", "next meeting",
+		"next event", "anything on", "agenda", "responded", "replied", "rsvp", "rsvped", "accepted",
+		"declined", "who's coming", "who is coming"},
+}, map[string][]string{
+	IntentView: {"get", "check", "see", "find", "look at", "view", "tell me", "any"},
+}, map[string][]string{
+	IntentDelete: {"clear", "drop"},
+	IntentCreate: {"plan"},
+}, intentKeyword{
+	// "find 45 minutes for a code review" asks for a slot, "find free time" doesn't
+	intent:  IntentCreate,
+	phrase:  "find <duration>",
+	pattern: regexp.MustCompile(`\bfind (?:\d+(?:\.\d+)?\s*(?:minutes?|mins?|hours?|hrs?)|an hour|half an hour)\b`),
+}, intentKeyword{
+	// A reminder is only a change when it's for an event that exists, as in
+	// "add a reminder to my dentist appointment"; otherwise it is a slot of
+	// whatever the request asks for
+	intent:  IntentUpdate,
+	phrase:  "reminder for an existing event",
+	pattern: regexp.MustCompile(`(?:\b(?:add|set|put|give)\s+(?:[\w'-]+\s+){0,4}?)?\b(?:reminders?\b[^.?!]*?\b(?:to|for|on|before|about|of)|remind me\b[^.?!]*?\b(?:before|about|of))\s+(?:my|the|our|this|that|today's|tomorrow's)\b`),
+})
+
+// commandPrefix is what may come before a command phrase, e.g. "can you"
+const commandPrefix = `^\s*(?:please\s+|(?:can|could|would|will) you\s+(?:please\s+)?)?`
+
+// ambiguousConfidence is the most confidence the rules have in a request
+// that uses a command phrase other than as a command, like "is my
+// afternoon clear?", so the AI decides
+const ambiguousConfidence = 0.6
+
+// ambiguousPattern matches the command phrases anywhere in a request
+var ambiguousPattern = buildAmbiguousPattern(intentKeywords)
+
+// buildIntentKeywords compiles word-boundary patterns for the phrases,
+// longest first so "my schedule" is matched before "schedule". Patterns,
+// keywords that need more than a phrase, are matched before any phrase.
+func buildIntentKeywords(strong, weak, commands map[string][]string, patterns ...intentKeyword) []intentKeyword {
+	var keywords []intentKeyword
+	add := func(phrases map[string][]string, isWeak, isCommand bool) {
+		for intent, list := range phrases {
+			for _, phrase := range list {
+				prefix := `\b`
+				if isCommand {
+					prefix = commandPrefix
+				}
+				keywords = append(keywords, intentKeyword{
+					intent:  intent,
+					phrase:  phrase,
+					weak:    isWeak,
+					command: isCommand,
+					pattern: regexp.MustCompile(prefix + regexp.QuoteMeta(phrase) + `\b`),
+				})
+			}
+		}
+	}
+	add(strong, false, false)
+	add(weak, true, false)
+	add(commands, false, true)
+
+	sort.SliceStable(keywords, func(i, j int) bool {
+		if len(keywords[i].phrase) != len(keywords[j].phrase) {
+			return len(keywords[i].phrase) > len(keywords[j].phrase)
+		}
+		return keywords[i].phrase < keywords[j].phrase
+	})
+	return append(patterns, keywords...)
+}
+
+// buildAmbiguousPattern matches any of the command phrases on word boundaries
+func buildAmbiguousPattern(keywords []intentKeyword) *regexp.Regexp {
+	var phrases []string
+	for _, keyword := range keywords {
+		if keyword.command {
+			phrases = append(phrases, regexp.QuoteMeta(keyword.phrase))
+		}
+	}
+	sort.Strings(phrases)
+	return regexp.MustCompile(`\b(?:` + strings.Join(phrases, "|") + `)\b`)
+}
+
+var (
+	clockTimePattern    = regexp.MustCompile(`\b(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b|\b(\d{1,2}):(\d{2})\b`)
+	durationPattern     = regexp.MustCompile(`\b(\d+(?:\.\d+)?)\s*(minutes?|mins?|hours?|hrs?|h)\b`)
+	noonPattern         = regexp.MustCompile(`\bnoon\b`)
+	isoDatePattern      = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})\b`)
+	reminderPattern     = regexp.MustCompile(`\b(\d+|an?|one)\s*(minutes?|mins?|hours?|hrs?|days?)\s+(?:before|ahead|earlier)\b|\bthe (day|night) before\b`)
+	participantsPattern = regexp.MustCompile(`\bwith\s+([A-Z][\w'-]*(?:(?:\s*,\s*|\s+and\s+|\s+)[A-Z][\w'-]*)*)`)
+	participantSplit    = regexp.MustCompile(`\s*,\s*|\s+and\s+`)
+)
+
+// IntentClassifier decides what a unified query asks for
+type IntentClassifier struct {
+	aiManager       *ai.Manager
+	promptGenerator *PromptGenerator
+}
+
+// NewIntentClassifier creates a new intent classifier
+func NewIntentClassifier(aiManager *ai.Manager, promptGenerator *PromptGenerator) *IntentClassifier {
+	return &IntentClassifier{
+		aiManager:       aiManager,
+		promptGenerator: promptGenerator,
+	}
+}
+
+// Classify uses the keyword rules when they are confident, and otherwise
+// asks the AI. If the AI is unavailable the rules' best guess is returned.
+// Slots the AI leaves out are filled from the rules' extraction.
+func (ic *IntentClassifier) Classify(question string) IntentResult {
+	result := ic.ClassifyWithRules(question)
+	if result.Confidence >= RuleConfidenceThreshold || ic.aiManager == nil || !ic.aiManager.HasClients() {
+		return result
+	}
+
+	aiResult, err := ic.ClassifyWithAI(question)
+	if err != nil {
+		fmt.Printf("⚠️ AI intent classification failed, using rules: %v\n", err)
+		return result
+	}
+	aiResult.Slots = aiResult.Slots.withDefaults(result.Slots)
+	return aiResult
+}
+
+// ClassifyWithRules classifies a request by keywords. Confidence is high
+// when only one intent's strong keywords appear, and low when keywords of
+// several intents, or only weak ones, appear, or when a command phrase is
+// used other than as a command.
+func (ic *IntentClassifier) ClassifyWithRules(question string) IntentResult {
+	text := " " + strings.ToLower(strings.TrimSpace(question)) + " "
+
+	scores := make(map[string]float64)
+	strong := make(map[string]bool)
+	for _, keyword := range intentKeywords {
+		if !keyword.pattern.MatchString(text) {
+			continue
+		}
+		// Blank out the match so shorter phrases inside it don't count again
+		text = keyword.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return strings.Repeat(" ", len(match))
+		})
+		if keyword.weak {
+			scores[keyword.intent] += 0.5
+		} else {
+			scores[keyword.intent] += 1
+			strong[keyword.intent] = true
+		}
+	}
+
+	result := IntentResult{Intent: IntentView, Confidence: 0.3, Source: SourceRules}
+
+	best := ""
+	var total float64
+	for _, intent := range intentPriority {
+		total += scores[intent]
+		if scores[intent] > 0 && (best == "" || scores[intent] > scores[best]) {
+			best = intent
+		}
+	}
+	if best != "" {
+		result.Intent = best
+	}
+
+	switch {
+	case total == 0:
+		// Nothing matched; a question is most likely a view
+	case len(scores) == 1 && strong[result.Intent]:
+		result.Confidence = 0.9
+	case len(scores) == 1:
+		result.Confidence = 0.6
+	default:
+		result.Confidence = 0.7 * scores[result.Intent] / total
+	}
+	// Command phrases used as commands were blanked out above
+	if ambiguousPattern.MatchString(text) && result.Confidence > ambiguousConfidence {
+		result.Confidence = ambiguousConfidence
+	}
+
+	result.Slots = ic.extractSlots(question)
+	return result
+}
+
+// ClassifyWithAI asks the AI for the intent and slots of a request
+func (ic *IntentClassifier) ClassifyWithAI(question string) (IntentResult, error) {
+	prompt := ic.promptGenerator.CreateIntentPrompt(question)
+	response, err := ic.aiManager.GeneratePlan(prompt)
+	if err != nil {
+		return IntentResult{}, err
+	}
+
+	var result IntentResult
+	if err := json.Unmarshal([]byte(utils.StripCodeFence(response)), &result); err != nil {
+		return IntentResult{}, fmt.Errorf("failed to parse intent JSON: %v", err)
+	}
+
+	result.Intent = strings.ToLower(strings.TrimSpace(result.Intent))
+	if !isKnownIntent(result.Intent) {
+		return IntentResult{}, fmt.Errorf("unknown intent %q", result.Intent)
+	}
+	if result.Confidence < 0 {
+		result.Confidence = 0
+	} else if result.Confidence > 1 {
+		result.Confidence = 1
+	}
+	result.Source = SourceAI
+	return result, nil
+}
+
+// withDefaults fills empty slots from other
+func (s IntentSlots) withDefaults(other IntentSlots) IntentSlots {
+	if s.Title == "" {
+		s.Title = other.Title
+	}
+	if s.Date == "" {
+		s.Date = other.Date
+	}
+	if s.Time == "" {
+		s.Time = other.Time
+	}
+	if s.Duration == "" {
+		s.Duration = other.Duration
+	}
+	if s.Reminder == "" {
+		s.Reminder = other.Reminder
+	}
+	if len(s.Participants) == 0 {
+		s.Participants = other.Participants
+	}
+	return s
+}
+
+// isKnownIntent checks an intent name
+func isKnownIntent(intent string) bool {
+	for _, known := range intentPriority {
+		if intent == known {
+			return true
+		}
+	}
+	return false
+}
+
+// extractSlots pulls the date, time, duration, participants and reminder
+// out of a request. The title is left to the AI.
+func (ic *IntentClassifier) extractSlots(question string) IntentSlots {
+	var slots IntentSlots
+	lower := strings.ToLower(question)
+	now := ic.promptGenerator.now()
+
+	switch {
+	case strings.Contains(lower, "day after tomorrow"):
+		slots.Date = now.AddDate(0, 0, 2).Format("2006-01-02")
+	case strings.Contains(lower, "tomorrow"):
+		slots.Date = now.AddDate(0, 0, 1).Format("2006-01-02")
+	case strings.Contains(lower, "today") || strings.Contains(lower, "tonight"):
+		slots.Date = now.Format("2006-01-02")
+	default:
+		if match := isoDatePattern.FindStringSubmatch(lower); match != nil {
+			slots.Date = match[1]
+		} else {
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				if strings.Contains(lower, strings.ToLower(day.String())) {
+					// The next such day, counting today
+					slots.Date = now.AddDate(0, 0, (int(day)-int(now.Weekday())+7)%7).Format("2006-01-02")
+					break
+				}
+			}
+		}
+	}
+
+	if match := clockTimePattern.FindStringSubmatch(lower); match != nil {
+		if match[3] != "" {
+			hour, _ := strconv.Atoi(match[1])
+			minute, _ := strconv.Atoi(match[2])
+			if hour == 12 {
+				hour = 0
+			}
+			if match[3] == "pm" {
+				hour += 12
+			}
+			if hour < 24 && minute < 60 {
+				slots.Time = fmt.Sprintf("%02d:%02d", hour, minute)
+			}
+		} else {
+			hour, _ := strconv.Atoi(match[4])
+			minute, _ := strconv.Atoi(match[5])
+			if hour < 24 && minute < 60 {
+				slots.Time = fmt.Sprintf("%02d:%02d", hour, minute)
+			}
+		}
+	} else if noonPattern.MatchString(lower) {
+		slots.Time = "12:00"
+	}
+
+	// A reminder's lead time isn't the event's duration
+	if match := reminderPattern.FindStringSubmatch(lower); match != nil {
+		slots.Reminder = reminderLead(match)
+		lower = strings.Replace(lower, match[0], "", 1)
+	}
+
+	if match := durationPattern.FindStringSubmatch(lower); match != nil {
+		amount, _ := strconv.ParseFloat(match[1], 64)
+		unit := time.Minute
+		if strings.HasPrefix(match[2], "h") {
+			unit = time.Hour
+		}
+		slots.Duration = formatDuration(time.Duration(amount * float64(unit)))
+	} else if strings.Contains(lower, "half an hour") || strings.Contains(lower, "half hour") {
+		slots.Duration = "30m"
+	} else if strings.Contains(lower, "an hour") {
+		slots.Duration = "1h"
+	}
+
+	if match := participantsPattern.FindStringSubmatch(question); match != nil {
+		for _, name := range participantSplit.Split(match[1], -1) {
+			if name = strings.TrimSpace(name); name != "" {
+				slots.Participants = append(slots.Participants, name)
+			}
+		}
+	}
+
+	return slots
+}
+
+// reminderLead formats the lead time of a reminderPattern match
+func reminderLead(match []string) string {
+	if match[3] != "" {
+		return formatDuration(24 * time.Hour)
+	}
+
+	amount, err := strconv.Atoi(match[1])
+	if err != nil {
+		amount = 1 // "an hour", "one day"
+	}
+	unit := time.Minute
+	switch {
+	case strings.HasPrefix(match[2], "h"):
+		unit = time.Hour
+	case strings.HasPrefix(match[2], "d"):
+		unit = 24 * time.Hour
+	}
+	return formatDuration(time.Duration(amount) * unit)
+}
+
+// formatDuration formats a duration without zero units, e.g. "1h30m" or "45m"
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,148 @@
+package planner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LabeledIntent is a request with its expected intent
+type LabeledIntent struct {
+	Question string `json:"question"`
+	Intent   string `json:"intent"`
+}
+
+// IntentMiss is a corpus request that was classified wrongly
+type IntentMiss struct {
+	LabeledIntent
+	Got IntentResult `json:"got"`
+}
+
+// IntentReport summarizes how well the classifier does on a corpus
+type IntentReport struct {
+	Total     int                       `json:"total"`
+	Correct   int                       `json:"correct"`
+	ByIntent  map[string][2]int         `json:"by_intent"` // intent -> correct, total
+	Confusion map[string]map[string]int `json:"confusion"` // expected -> got -> count
+	Misses    []IntentMiss              `json:"misses,omitempty"`
+	AIUsed    int                       `json:"ai_used"` // requests the rules were unsure of
+}
+
+// Accuracy returns the share of correctly classified requests
+func (r IntentReport) Accuracy() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Total)
+}
+
+// LoadIntentCorpus reads a labeled corpus, one JSON object per line. Blank
+// lines and lines starting with # are skipped.
+func LoadIntentCorpus(path string) ([]LabeledIntent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open intent corpus: %v", err)
+	}
+	defer file.Close()
+
+	var corpus []LabeledIntent
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var example LabeledIntent
+		if err := json.Unmarshal([]byte(line), &example); err != nil {
+			return nil, fmt.Errorf("invalid intent corpus line %d: %v", lineNumber, err)
+		}
+		if !isKnownIntent(example.Intent) {
+			return nil, fmt.Errorf("unknown intent %q on intent corpus line %d", example.Intent, lineNumber)
+		}
+		corpus = append(corpus, example)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read intent corpus: %v", err)
+	}
+
+	return corpus, nil
+}
+
+// Evaluate classifies every corpus request and compares it with its label.
+// With rulesOnly the AI is never asked, which measures the keyword rules alone.
+func (ic *IntentClassifier) Evaluate(corpus []LabeledIntent, rulesOnly bool) IntentReport {
+	report := IntentReport{
+		ByIntent:  make(map[string][2]int),
+		Confusion: make(map[string]map[string]int),
+	}
+
+	for _, example := range corpus {
+		var got IntentResult
+		if rulesOnly {
+			got = ic.ClassifyWithRules(example.Question)
+		} else {
+			got = ic.Classify(example.Question)
+		}
+		if got.Source == SourceAI {
+			report.AIUsed++
+		}
+
+		report.Total++
+		counts := report.ByIntent[example.Intent]
+		counts[1]++
+		if got.Intent == example.Intent {
+			report.Correct++
+			counts[0]++
+		} else {
+			report.Misses = append(report.Misses, IntentMiss{LabeledIntent: example, Got: got})
+		}
+		report.ByIntent[example.Intent] = counts
+
+		if report.Confusion[example.Intent] == nil {
+			report.Confusion[example.Intent] = make(map[string]int)
+		}
+		report.Confusion[example.Intent][got.Intent]++
+	}
+
+	return report
+}
+
+// PrintReport prints the accuracy, per-intent results and misclassified requests
+func (r IntentReport) PrintReport() {
+	fmt.Printf("🎯 Intent accuracy: %d/%d (%.1f%%)\n", r.Correct, r.Total, 100*r.Accuracy())
+	if r.AIUsed > 0 {
+		fmt.Printf("🤖 AI classified %d request(s) the rules were unsure of\n", r.AIUsed)
+	}
+
+	var intents []string
+	for intent := range r.ByIntent {
+		intents = append(intents, intent)
+	}
+	sort.Strings(intents)
+	for _, intent := range intents {
+		counts := r.ByIntent[intent]
+		fmt.Printf("   %-7s %3d/%-3d", intent, counts[0], counts[1])
+		var confusions []string
+		for got, count := range r.Confusion[intent] {
+			if got != intent {
+				confusions = append(confusions, fmt.Sprintf("%d as %s", count, got))
+			}
+		}
+		sort.Strings(confusions)
+		if len(confusions) > 0 {
+			fmt.Printf("  (%s)", strings.Join(confusions, ", "))
+		}
+		fmt.Println()
+	}
+
+	if len(r.Misses) > 0 {
+		fmt.Println("\n❌ Misclassified:")
+		for _, miss := range r.Misses {
+			fmt.Printf("   %q: expected %s, got %s (%.2f, %s)\n", miss.Question, miss.Intent, miss.Got.Intent, miss.Got.Confidence, miss.Got.Source)
+		}
+	}
+}
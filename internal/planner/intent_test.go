@@ -0,0 +1,100 @@
+package planner
+
+import (
+	"testing"
+	"time"
+)
+
+// minIntentAccuracy is the share of each intent's corpus requests the
+// keyword rules must get right. Creates without a verb ("yoga class every
+// Wednesday") are left to the AI.
+var minIntentAccuracy = map[string]float64{
+	IntentCreate: 0.8,
+	IntentView:   0.95,
+	IntentUpdate: 0.95,
+	IntentDelete: 0.95,
+	IntentUndo:   1,
+}
+
+func newTestClassifier() *IntentClassifier {
+	return NewIntentClassifier(nil, NewPromptGenerator(time.UTC))
+}
+
+func TestIntentCorpusAccuracy(t *testing.T) {
+	corpus, err := LoadIntentCorpus("testdata/intents.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := newTestClassifier().Evaluate(corpus, true)
+	for _, miss := range report.Misses {
+		t.Logf("%q: expected %s, got %s (%.2f)", miss.Question, miss.Intent, miss.Got.Intent, miss.Got.Confidence)
+	}
+
+	for intent, min := range minIntentAccuracy {
+		counts := report.ByIntent[intent]
+		if counts[1] == 0 {
+			t.Errorf("%s: no corpus requests", intent)
+			continue
+		}
+		if accuracy := float64(counts[0]) / float64(counts[1]); accuracy < min {
+			t.Errorf("%s: %d/%d correct (%.2f), want at least %.2f", intent, counts[0], counts[1], accuracy, min)
+		}
+	}
+}
+
+func TestClassifyWithRules(t *testing.T) {
+	tests := []struct {
+		question string
+		intent   string
+		trusted  bool // confidence at or above RuleConfidenceThreshold
+	}{
+		{"Delete my dentist appointment", IntentDelete, true},
+		{"Drop the 3pm call", IntentDelete, true},
+		{"Can you clear my Friday afternoon?", IntentDelete, true},
+		{"Plan lunch with Sarah on Thursday at noon", IntentCreate, true},
+		{"Get me a dentist appointment Friday", IntentCreate, true},
+		{"Find 45 minutes for a code review sometime tomorrow afternoon", IntentCreate, true},
+		{"Find free time on Thursday", IntentView, true},
+		{"Is my afternoon clear?", IntentView, false},
+		{"Can I drop by the office tomorrow?", IntentView, false},
+		{"What should I plan for tomorrow?", IntentView, false},
+		{"Show me what I can drop this week", IntentView, false},
+		{"Book a dentist appointment Friday at 3pm with a reminder", IntentCreate, true},
+		{"Book a dentist appointment tomorrow at 3pm and remind me an hour before", IntentCreate, true},
+		{"Add a reminder to my dentist appointment", IntentUpdate, true},
+		{"Remind me 30 minutes before my dentist appointment", IntentUpdate, true},
+	}
+
+	classifier := newTestClassifier()
+	for _, test := range tests {
+		got := classifier.ClassifyWithRules(test.question)
+		if got.Intent != test.intent {
+			t.Errorf("%q: intent %s, want %s", test.question, got.Intent, test.intent)
+		}
+		if trusted := got.Confidence >= RuleConfidenceThreshold; trusted != test.trusted {
+			t.Errorf("%q: confidence %.2f, trusted %v, want %v", test.question, got.Confidence, trusted, test.trusted)
+		}
+	}
+}
+
+func TestExtractReminderSlot(t *testing.T) {
+	tests := []struct {
+		question string
+		reminder string
+		duration string
+	}{
+		{"Remind me 30 minutes before my dentist appointment", "30m", ""},
+		{"Book a 2 hour workshop tomorrow and remind me an hour before", "1h", "2h"},
+		{"Set a reminder for the review the day before", "24h", ""},
+		{"Book a dentist appointment Friday at 3pm with a reminder", "", ""},
+	}
+
+	classifier := newTestClassifier()
+	for _, test := range tests {
+		slots := classifier.ClassifyWithRules(test.question).Slots
+		if slots.Reminder != test.reminder || slots.Duration != test.duration {
+			t.Errorf("%q: reminder %q, duration %q, want %q, %q", test.question, slots.Reminder, slots.Duration, test.reminder, test.duration)
+		}
+	}
+}
@@ -4,30 +4,89 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
 )
 
 // PromptGenerator handles AI prompt generation
-type PromptGenerator struct{}
+type PromptGenerator struct {
+	location     *time.Location
+	conversation *models.Conversation  // earlier turns of the session, if any
+	availability *calendar.Availability // when events may be booked; nil means the default profile
+}
+
+// NewPromptGenerator creates a new prompt generator for the given timezone
+func NewPromptGenerator(location *time.Location) *PromptGenerator {
+	if location == nil {
+		location = time.Local
+	}
+	return &PromptGenerator{location: location}
+}
+
+// now returns the current time in the generator's timezone
+func (p *PromptGenerator) now() time.Time {
+	return time.Now().In(p.location)
+}
+
+// WithLocation returns a prompt generator working in another timezone
+func (p *PromptGenerator) WithLocation(location *time.Location) *PromptGenerator {
+	generator := *p
+	generator.location = location
+	return &generator
+}
+
+// WithAvailability returns a prompt generator whose prompts ask for events
+// within the given availability profile
+func (p *PromptGenerator) WithAvailability(availability *calendar.Availability) *PromptGenerator {
+	generator := *p
+	generator.availability = availability
+	return &generator
+}
 
-// NewPromptGenerator creates a new prompt generator
-func NewPromptGenerator() *PromptGenerator {
-	return &PromptGenerator{}
+// WithConversation returns a prompt generator whose prompts include the
+// earlier turns of a session
+func (p *PromptGenerator) WithConversation(conversation *models.Conversation) *PromptGenerator {
+	generator := *p
+	generator.conversation = conversation
+	return &generator
+}
+
+// conversationSection describes the session's earlier turns, or is empty
+// outside a session
+func (p *PromptGenerator) conversationSection() string {
+	section := utils.FormatConversation(p.conversation)
+	if section == "" {
+		return ""
+	}
+	return "\n" + section
+}
+
+// availabilitySection describes when events may be booked
+func (p *PromptGenerator) availabilitySection() string {
+	return "\nAVAILABILITY (schedule inside the working hours unless the user asks for a specific time):\n" +
+		p.availability.Profile().Describe(p.now())
+}
+
+// timeZoneInstruction describes the timezone and today's UTC offset for the LLM
+func (p *PromptGenerator) timeZoneInstruction() string {
+	return fmt.Sprintf("Timezone: %s (UTC offset %s)", p.location.String(), p.now().Format("-07:00"))
 }
 
 // CreatePlanningPrompt creates a comprehensive prompt for AI planning
 func (p *PromptGenerator) CreatePlanningPrompt(existingTasks []models.Task, userInput string) string {
-	now := time.Now()
+	now := p.now()
 	today := now.Format("2006-01-02")
+	offset := now.Format("-07:00")
 
 	prompt := fmt.Sprintf(`You are a personal assistant helping to plan a daily schedule. 
 
 Today's date: %s
 Current time: %s
+%s
 
 EXISTING CALENDAR EVENTS (DO NOT DUPLICATE THESE):
-`, today, now.Format("15:04"))
+`, today, now.Format("15:04"), p.timeZoneInstruction()+p.availabilitySection())
 
 	if len(existingTasks) == 0 {
 		prompt += "No existing events found.\n"
@@ -55,14 +114,14 @@ Respond with ONLY a valid JSON array containing ONLY the NEW tasks:
 [
   {
     "summary": "New Task Name",
-    "start": "%sT12:30:00+05:30",
-    "end": "%sT13:00:00+05:30"
+    "start": "%sT12:30:00%s",
+    "end": "%sT13:00:00%s"
   }
 ]
 
-Use ISO 8601 format with +05:30 timezone. Make sure the JSON is valid and parseable.
+Use ISO 8601 format with the %s offset. Make sure the JSON is valid and parseable.
 REMEMBER: Only return NEW tasks, not existing ones!`, 
-		userInput, today, today)
+		userInput, today, offset, today, offset, offset)
 
 	return prompt
 }
@@ -71,7 +130,9 @@ REMEMBER: Only return NEW tasks, not existing ones!`,
 // CreateRestrictivePrompt creates a prompt that only creates what's specifically requested
 // CreateRestrictivePrompt creates a prompt that only creates what's specifically requested
 func (pg *PromptGenerator) CreateRestrictivePrompt(existingTasks []models.Task, userInput string) string {
-	currentTime := time.Now().Format("2006-01-02 15:04:05")
+	now := pg.now()
+	currentTime := now.Format("2006-01-02 15:04:05")
+	offset := now.Format("-07:00")
 	
 	existingEventsStr := ""
 	if len(existingTasks) > 0 {
@@ -92,33 +153,165 @@ IMPORTANT RULES:
 
 Current time: %s
 %s
+%s
 
 User request: "%s"
 
 Respond with a JSON array of events. Each event should have:
 - summary: Event title
-- start: Start time in RFC3339 format (YYYY-MM-DDTHH:MM:SS+05:30)
+- start: Start time in RFC3339 format (YYYY-MM-DDTHH:MM:SS%s)
 - end: End time in RFC3339 format
+- recurrence: ONLY for repeating events ("every Monday", "daily", "each month"), an array with one RRULE string
+- attendees: ONLY when the user names people the event is with, an array of {"name": "...", "email": "..."} (email only if the user gave it)
+- location: ONLY when the user says where the event is
+- description: ONLY when the user gives notes or an agenda for the event
+- conference: ONLY when the user asks for a video call (Meet, Zoom, "video call"), {"create": true}
+- reminders: ONLY when the user asks to be reminded, an array of {"method": "popup" or "email", "minutes": minutes before the start}, e.g. "remind me 30 minutes before" is [{"method": "popup", "minutes": 30}]
+
+For a repeating event create ONE event: start and end are the FIRST occurrence, and
+the rule describes the repetition. Use FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), and
+optionally BYDAY (MO,TU,WE,TH,FR,SA,SU), INTERVAL, COUNT or UNTIL (YYYYMMDDTHHMMSSZ in UTC).
+Never list the occurrences of a repeating event as separate events.
 
 Example response:
 [
   {
     "summary": "Team Meeting",
-    "start": "2025-07-15T14:00:00+05:30",
-    "end": "2025-07-15T15:00:00+05:30"
+    "start": "2025-07-15T14:00:00%s",
+    "end": "2025-07-15T15:00:00%s"
+  },
+  {
+    "summary": "Gym",
+    "start": "2025-07-14T07:00:00%s",
+    "end": "2025-07-14T08:00:00%s",
+    "recurrence": ["RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20251201T235959Z"]
   }
 ]
 
-Only respond with the JSON array, no additional text.`, currentTime, existingEventsStr, userInput)
+Only respond with the JSON array, no additional text.`, currentTime, pg.timeZoneInstruction()+pg.availabilitySection(), existingEventsStr+pg.conversationSection(), userInput, offset, offset, offset, offset, offset)
+
+	return prompt
+}
+
+
+// CreateUpdatePrompt creates a prompt asking the AI which existing event to
+// change and what its new details are
+func (p *PromptGenerator) CreateUpdatePrompt(existingTasks []models.Task, userInput string) string {
+	now := p.now()
+	offset := now.Format("-07:00")
+
+	prompt := fmt.Sprintf(`You are a calendar assistant. The user wants to change ONE existing event (move, reschedule or rename it, or set its reminders).
+
+Current time: %s
+%s
+
+EXISTING EVENTS:
+`, now.Format("2006-01-02 15:04:05"), p.timeZoneInstruction()+p.availabilitySection())
+
+	if len(existingTasks) == 0 {
+		prompt += "No existing events found.\n"
+	}
+	for _, task := range existingTasks {
+		prompt += fmt.Sprintf("- [id: %s] %s (%s to %s)\n", task.EventID, task.Summary, task.Start, task.End)
+	}
+	prompt += p.conversationSection()
+
+	prompt += fmt.Sprintf(`
+User request: "%s"
+
+Respond with a JSON object describing the change:
+{
+  "event_id": "ID of the event to change, copied exactly from the list above",
+  "summary": "New title, or empty to keep the current title",
+  "start": "New start time in RFC3339 format (YYYY-MM-DDTHH:MM:SS%s), or empty to keep it",
+  "end": "New end time in RFC3339 format, or empty to keep the current duration",
+  "reminders": [{"method": "popup" or "email", "minutes": minutes before the start}] (ONLY when the user asks to be reminded)
+}
+
+IMPORTANT RULES:
+1. Pick the single event that best matches the request
+2. Only fill in the fields the user wants to change
+3. If only a new start time is given, leave end empty
+
+Only respond with the JSON object, no additional text.`, userInput, offset)
+
+	return prompt
+}
+
+// CreateToolPrompt creates a prompt asking the AI to act on a request by
+// calling one of the calendar tools
+func (p *PromptGenerator) CreateToolPrompt(existingTasks []models.Task, userInput string) string {
+	now := p.now()
+
+	prompt := fmt.Sprintf(`You are a calendar assistant. Handle the user's request by calling ONE of the tools.
+
+Current time: %s (%s)
+%s
+
+EXISTING EVENTS (today and the next 7 days):
+`, now.Format("2006-01-02 15:04:05"), now.Weekday(), p.timeZoneInstruction()+p.availabilitySection())
+
+	if len(existingTasks) == 0 {
+		prompt += "No existing events found.\n"
+	}
+	for _, task := range existingTasks {
+		prompt += fmt.Sprintf("- [id: %s] %s (%s to %s)\n", task.EventID, task.Summary, task.Start, task.End)
+	}
+	prompt += p.conversationSection()
+
+	prompt += fmt.Sprintf(`
+User request: "%s"
+
+IMPORTANT RULES:
+1. Create ONLY the events the user explicitly asks for, with realistic durations
+2. Refer to existing events by the id shown above, copied exactly
+3. Times are RFC3339 with the UTC offset (YYYY-MM-DDTHH:MM:SS%s)
+4. A repeating event is ONE event with an RRULE recurrence, starting at its first occurrence`, userInput, now.Format("-07:00"))
 
 	return prompt
 }
 
+// CreateIntentPrompt creates a prompt asking the AI what a request wants
+// done and which details it mentions
+func (p *PromptGenerator) CreateIntentPrompt(userInput string) string {
+	now := p.now()
+
+	return fmt.Sprintf(`You classify requests sent to a calendar assistant.
+
+Current time: %s (%s)
+%s
+
+User request: "%s"
+
+Respond with a JSON object:
+{
+  "intent": "create, view, update, delete or undo",
+  "confidence": 0.0 to 1.0,
+  "slots": {
+    "title": "Event title, if the request names or describes one",
+    "date": "YYYY-MM-DD, if the request mentions a day",
+    "time": "HH:MM in 24-hour time, if the request mentions a time",
+    "duration": "e.g. 30m or 1h30m, if the request mentions a duration",
+    "participants": ["names of the people the event is with"],
+    "reminder": "how long before the event to remind, e.g. 30m or 24h, if the request asks for a reminder"
+  }
+}
+
+Intents:
+- create: add a new event, with any reminders it asks for ("get me a dentist appointment Friday" is a create)
+- view: ask about the schedule without changing it
+- update: move, reschedule or rename an existing event, or add reminders to it
+- delete: delete, cancel or skip existing events
+- undo: reverse the assistant's previous change
+
+Leave out slots the request does not mention. Only respond with the JSON object, no additional text.`, now.Format("2006-01-02 15:04:05"), now.Weekday(), p.timeZoneInstruction()+p.conversationSection(), userInput)
+}
 
 // CreateReschedulingPrompt creates a prompt for rescheduling conflicting tasks
 func (p *PromptGenerator) CreateReschedulingPrompt(conflictingTasks []models.Task, existingTasks []models.Task) string {
-	now := time.Now()
+	now := p.now()
 	today := now.Format("2006-01-02")
+	offset := now.Format("-07:00")
 
 	prompt := fmt.Sprintf(`You need to reschedule the following conflicting tasks:
 
@@ -141,14 +334,16 @@ CONFLICTING TASKS:
 	}
 
 	prompt += fmt.Sprintf(`
+%s
+
 Please reschedule the conflicting tasks to avoid overlaps. Respond with ONLY a valid JSON array:
 [
   {
     "summary": "Task name",
-    "start": "%sT09:00:00+05:30",
-    "end": "%sT10:00:00+05:30"
+    "start": "%sT09:00:00%s",
+    "end": "%sT10:00:00%s"
   }
-]`, today, today)
+]`, p.timeZoneInstruction()+p.availabilitySection(), today, offset, today, offset)
 
 	return prompt
 }
@@ -11,30 +11,82 @@ import (
 	"github.com/Karan2980/llm-planner-golang-project/internal/ai"
 	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
-	calendarv3 "google.golang.org/api/calendar/v3"
 )
 
 // QueryHandler handles calendar queries
 type QueryHandler struct {
 	queryService    *calendar.QueryService
 	queryProcessor  *ai.QueryProcessor
+	aiManager       *ai.Manager
 	timeZone        string
+	location        *time.Location
+	conversation    *models.Conversation
 }
 
-// NewQueryHandler creates a new query handler
-func NewQueryHandler(calendarService *calendarv3.Service, aiConfig models.AIConfig, timeZone string) *QueryHandler {
-	calendarClient := calendar.NewClient(calendarService)
-	queryService := calendar.NewQueryService(calendarClient)
-	aiManager := ai.NewManager(aiConfig)
-	queryProcessor := ai.NewQueryProcessor(aiManager, calendarClient)
+// NewQueryHandler creates a query handler that answers from the scheduler's
+// calendar client, plus any additional calendars, and AI manager
+func NewQueryHandler(calendarClient *calendar.Client, aiManager *ai.Manager, additionalCalendarIDs ...string) *QueryHandler {
+	location := calendarClient.Location()
 
 	return &QueryHandler{
-		queryService:   queryService,
-		queryProcessor: queryProcessor,
-		timeZone:       timeZone,
+		queryService:   calendar.NewQueryService(calendarClient, additionalCalendarIDs...),
+		queryProcessor: ai.NewQueryProcessor(aiManager, calendarClient),
+		aiManager:      aiManager,
+		timeZone:       location.String(),
+		location:       location,
 	}
 }
 
+// WithCalendar returns a query handler that reads from another calendar
+func (qh *QueryHandler) WithCalendar(calendarID string) *QueryHandler {
+	if calendarID == "" {
+		return qh
+	}
+
+	handler := *qh
+	handler.queryService = qh.queryService.WithCalendar(calendarID)
+	handler.queryProcessor = ai.NewQueryProcessor(qh.aiManager, handler.queryService.GetClient())
+	return &handler
+}
+
+// WithLocation returns a query handler that works in the given timezone
+func (qh *QueryHandler) WithLocation(location *time.Location) *QueryHandler {
+	if location == nil || location == qh.location {
+		return qh
+	}
+
+	handler := *qh
+	handler.queryService = qh.queryService.WithLocation(location)
+	handler.queryProcessor = ai.NewQueryProcessor(qh.aiManager, handler.queryService.GetClient())
+	handler.timeZone = location.String()
+	handler.location = location
+	return &handler
+}
+
+// WithAvailability returns a query handler that answers free-time
+// questions within the given availability profile
+func (qh *QueryHandler) WithAvailability(availability *calendar.Availability) *QueryHandler {
+	handler := *qh
+	handler.queryService = qh.queryService.WithAvailability(availability)
+	return &handler
+}
+
+// WithPolicy returns a query handler that reports conflicts by the given
+// conflict policy
+func (qh *QueryHandler) WithPolicy(policy *calendar.ConflictPolicy) *QueryHandler {
+	handler := *qh
+	handler.queryService = qh.queryService.WithPolicy(policy)
+	return &handler
+}
+
+// WithConversation returns a query handler that answers with the earlier
+// turns of a session in mind
+func (qh *QueryHandler) WithConversation(conversation *models.Conversation) *QueryHandler {
+	handler := *qh
+	handler.conversation = conversation
+	return &handler
+}
+
 // HandleQuery processes a user query about their calendar
 func (qh *QueryHandler) HandleQuery(ctx context.Context, question string) (*models.QueryResponse, error) {
 	// Get calendar context
@@ -64,7 +116,7 @@ func (qh *QueryHandler) HandleQuery(ctx context.Context, question string) (*mode
 
 // buildQueryContext builds the context needed for query processing
 func (qh *QueryHandler) buildQueryContext() (*models.QueryContext, error) {
-	now := time.Now()
+	now := time.Now().In(qh.location)
 
 	// Get today's events
 	todaysEvents, err := qh.queryService.GetTodaysSchedule()
@@ -89,16 +141,34 @@ func (qh *QueryHandler) buildQueryContext() (*models.QueryContext, error) {
 		}
 		
 		// Only include events that are not today
-		if eventTime.Format("2006-01-02") != today {
+		if eventTime.In(qh.location).Format("2006-01-02") != today {
 			filteredUpcoming = append(filteredUpcoming, event)
 		}
 	}
 
+	// Free time left today, within the availability profile
+	availability := qh.queryService.GetAvailability().Profile()
+	var freeToday []models.TimeSlot
+	freeSlots, err := qh.queryService.GetFreeTimeSlots(now, 15*time.Minute)
+	if err == nil {
+		for _, slot := range freeSlots {
+			if slot.End.After(now) {
+				if slot.Start.Before(now) {
+					slot.Start = now
+				}
+				freeToday = append(freeToday, slot)
+			}
+		}
+	}
+
 	return &models.QueryContext{
 		CurrentTime:    now,
 		TodaysEvents:   todaysEvents,
 		UpcomingEvents: filteredUpcoming,
 		TimeZone:       qh.timeZone,
+		Availability:   &availability,
+		FreeToday:      freeToday,
+		Conversation:   qh.conversation,
 	}, nil
 }
 
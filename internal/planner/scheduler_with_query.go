@@ -7,12 +7,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Karan2980/llm-planner-golang-project/internal/ai"
 	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
 	"github.com/Karan2980/llm-planner-golang-project/internal/models"
 	"github.com/Karan2980/llm-planner-golang-project/pkg/utils"
-	calendarv3 "google.golang.org/api/calendar/v3"
 )
 
 // EnhancedScheduler includes both scheduling and query capabilities
@@ -22,6 +22,9 @@ type EnhancedScheduler struct {
 	conflictChecker  *ConflictChecker
 	promptGenerator  *PromptGenerator
 	queryHandler     *QueryHandler
+	availability     *calendar.Availability
+	slotPreferences  models.SlotPreferences
+	contacts         map[string]string // lowercase name -> email
 }
 
 // GetCalendarClient returns the calendar client
@@ -49,27 +52,69 @@ func (es *EnhancedScheduler) GetQueryHandler() *QueryHandler {
 	return es.queryHandler
 }
 
+// GetIntentClassifier returns an intent classifier working in the scheduler's timezone
+func (es *EnhancedScheduler) GetIntentClassifier() *IntentClassifier {
+	return NewIntentClassifier(es.aiManager, es.promptGenerator)
+}
+
+// GetAvailability returns the availability profile events are booked within
+func (es *EnhancedScheduler) GetAvailability() *calendar.Availability {
+	return es.availability
+}
+
 // GetQueryService returns the query service
 func (es *EnhancedScheduler) GetQueryService() *calendar.QueryService {
 	return es.queryHandler.queryService
 }
 
 // NewEnhancedScheduler creates a new enhanced scheduler with query capabilities
-func NewEnhancedScheduler(calendarService *calendarv3.Service, aiConfig models.AIConfig, timeZone string) *EnhancedScheduler {
+func NewEnhancedScheduler(backend calendar.CalendarBackend, aiConfig models.AIConfig, calendarConfig models.CalendarConfig) *EnhancedScheduler {
+	// Resolve the configured timezone
+	location := loadLocation(calendarConfig.TimeZone)
+
+	// Decide how attendees hear about changes
+	sendUpdates := calendarConfig.SendUpdates
+	if !models.ValidSendUpdates(sendUpdates) {
+		fmt.Printf("⚠️ Unknown send-updates mode %q, sending updates to all attendees\n", sendUpdates)
+		sendUpdates = models.SendUpdatesAll
+	}
+
 	// Create calendar client
-	calendarClient := calendar.NewClient(calendarService)
+	calendarClient := calendar.NewClient(backend, calendarConfig.CalendarID).WithLocation(location).WithSendUpdates(sendUpdates)
+	if calendarConfig.JournalPath != "" {
+		journal, err := calendar.OpenJournal(calendarConfig.JournalPath)
+		if err != nil {
+			fmt.Printf("⚠️ Undo is disabled: %v\n", err)
+		} else {
+			calendarClient = calendarClient.WithJournal(journal)
+		}
+	}
+	
+	// Load the availability profile
+	availability, err := calendar.OpenAvailability(calendarConfig.AvailabilityPath)
+	if err != nil {
+		fmt.Printf("⚠️ Using the default availability profile: %v\n", err)
+		availability, _ = calendar.OpenAvailability("")
+	}
+	
+	// Load the conflict policy
+	policy, err := calendar.LoadConflictPolicy(calendarConfig.ConflictPolicyPath)
+	if err != nil {
+		fmt.Printf("⚠️ Using the default conflict policy: %v\n", err)
+		policy = calendar.DefaultConflictPolicy()
+	}
 	
 	// Create AI manager
 	aiManager := ai.NewManager(aiConfig)
 	
 	// Create conflict checker
-	conflictChecker := NewConflictChecker()
+	conflictChecker := NewConflictChecker(availability, policy)
 	
 	// Create prompt generator
-	promptGenerator := NewPromptGenerator()
+	promptGenerator := NewPromptGenerator(location).WithAvailability(availability)
 	
 	// Create query handler
-	queryHandler := NewQueryHandler(calendarService, aiConfig, timeZone)
+	queryHandler := NewQueryHandler(calendarClient, aiManager, calendarConfig.AdditionalCalendarIDs...).WithAvailability(availability).WithPolicy(policy)
 
 	return &EnhancedScheduler{
 		calendarClient:   calendarClient,
@@ -77,7 +122,91 @@ func NewEnhancedScheduler(calendarService *calendarv3.Service, aiConfig models.A
 		conflictChecker:  conflictChecker,
 		promptGenerator:  promptGenerator,
 		queryHandler:     queryHandler,
+		availability:     availability,
+		slotPreferences:  calendarConfig.SlotPreferences,
+		contacts:         calendarConfig.Contacts,
+	}
+}
+
+// WithCalendar returns a scheduler that reads from and writes to another calendar.
+// An empty calendarID returns the scheduler unchanged.
+func (es *EnhancedScheduler) WithCalendar(calendarID string) *EnhancedScheduler {
+	if calendarID == "" {
+		return es
+	}
+
+	scheduler := *es
+	scheduler.calendarClient = es.calendarClient.WithCalendar(calendarID)
+	scheduler.queryHandler = es.queryHandler.WithCalendar(calendarID)
+	return &scheduler
+}
+
+// WithTimeZone returns a scheduler that works in the given IANA timezone.
+// An empty timeZone returns the scheduler unchanged.
+func (es *EnhancedScheduler) WithTimeZone(timeZone string) (*EnhancedScheduler, error) {
+	if timeZone == "" {
+		return es, nil
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", timeZone, err)
+	}
+
+	scheduler := *es
+	scheduler.calendarClient = es.calendarClient.WithLocation(location)
+	scheduler.queryHandler = es.queryHandler.WithLocation(location)
+	scheduler.promptGenerator = es.promptGenerator.WithLocation(location)
+	return &scheduler, nil
+}
+
+// WithSendUpdates returns a scheduler that tells attendees about new and
+// changed events in the given way: "all", "externalOnly" or "none". An
+// empty sendUpdates returns the scheduler unchanged.
+func (es *EnhancedScheduler) WithSendUpdates(sendUpdates string) (*EnhancedScheduler, error) {
+	if sendUpdates == "" {
+		return es, nil
+	}
+	if !models.ValidSendUpdates(sendUpdates) {
+		return nil, fmt.Errorf("invalid send_updates %q, expected %s, %s or %s", sendUpdates, models.SendUpdatesAll, models.SendUpdatesExternal, models.SendUpdatesNone)
+	}
+
+	scheduler := *es
+	scheduler.calendarClient = es.calendarClient.WithSendUpdates(sendUpdates)
+	return &scheduler, nil
+}
+
+// WithConversation returns a scheduler whose prompts include the earlier
+// turns of a session. A nil conversation returns the scheduler unchanged.
+func (es *EnhancedScheduler) WithConversation(conversation *models.Conversation) *EnhancedScheduler {
+	if conversation == nil {
+		return es
 	}
+
+	scheduler := *es
+	scheduler.promptGenerator = es.promptGenerator.WithConversation(conversation)
+	scheduler.queryHandler = es.queryHandler.WithConversation(conversation)
+	return &scheduler
+}
+
+// GetLocation returns the timezone the scheduler works in
+func (es *EnhancedScheduler) GetLocation() *time.Location {
+	return es.calendarClient.Location()
+}
+
+// loadLocation resolves an IANA timezone name, falling back to the local
+// timezone when it is empty or unknown
+func loadLocation(timeZone string) *time.Location {
+	if timeZone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		fmt.Printf("⚠️ Unknown timezone %q, using local time: %v\n", timeZone, err)
+		return time.Local
+	}
+	return location
 }
 
 // Run executes the main application with both scheduling and query options
@@ -138,8 +267,8 @@ func (es *EnhancedScheduler) showMainMenu(ctx context.Context) error {
 func (es *EnhancedScheduler) HandleScheduling(ctx context.Context) error {
 	fmt.Println("\n📅 SCHEDULING MODE")
 	
-	// Get existing events
-	existingTasks, err := es.calendarClient.GetTodaysEvents()
+	// Get existing events across all configured calendars
+	existingTasks, err := es.GetQueryService().GetTodaysSchedule()
 	if err != nil {
 		fmt.Printf("⚠️ Warning: Could not read existing events: %v\n", err)
 		existingTasks = []models.Task{}
@@ -253,7 +382,17 @@ func (es *EnhancedScheduler) executePlan(tasks []models.Task, existingTasks []mo
 	// Filter out conflicting tasks
 	var validTasks []models.Task
 	for _, task := range tasks {
-		if !es.conflictChecker.HasTimeConflict(task, existingTasks) {
+		if task.IsRecurring() {
+			conflicts, err := es.FindRecurringConflicts(task)
+			if err != nil {
+				fmt.Printf("⚠️ Skipping recurring task %s: %v\n", task.Summary, err)
+			} else if len(conflicts) > 0 {
+				fmt.Printf("⚠️ Skipping recurring task %s: %d occurrence(s) conflict, first on %s\n",
+					task.Summary, len(conflicts), utils.FormatDateTime(conflicts[0].Start))
+			} else {
+				validTasks = append(validTasks, task)
+			}
+		} else if !es.conflictChecker.HasTimeConflict(task, existingTasks) {
 			validTasks = append(validTasks, task)
 		} else {
 			fmt.Printf("⚠️ Skipping conflicting task: %s\n", task.Summary)
@@ -275,6 +414,53 @@ func (es *EnhancedScheduler) executePlan(tasks []models.Task, existingTasks []mo
 	return nil
 }
 
+// recurrenceHorizon limits how far ahead open-ended recurring events are
+// checked for conflicts
+const recurrenceHorizon = 365 * 24 * time.Hour
+
+// ExpandOccurrences returns the occurrences of a recurring task within the
+// recurrence horizon
+func (es *EnhancedScheduler) ExpandOccurrences(task models.Task) ([]models.Task, error) {
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := calendar.ExpandRecurrence(task, parsed.StartTime, parsed.StartTime.Add(recurrenceHorizon))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence: %v", err)
+	}
+	return occurrences, nil
+}
+
+// FindRecurringConflicts expands a recurring task and returns the occurrences
+// that overlap existing events in any configured calendar
+func (es *EnhancedScheduler) FindRecurringConflicts(task models.Task) ([]models.Task, error) {
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := es.ExpandOccurrences(task)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) == 0 {
+		return nil, nil
+	}
+
+	last, err := occurrences[len(occurrences)-1].ParseTime()
+	if err != nil {
+		return nil, err
+	}
+	existingTasks, err := es.GetQueryService().GetEventsByDateRange(parsed.StartTime, last.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return es.conflictChecker.FindConflicts(occurrences, existingTasks), nil
+}
+
 // RunQuickQuery runs a single query without the interactive menu
 func (es *EnhancedScheduler) RunQuickQuery(ctx context.Context, question string) error {
 	fmt.Printf("🚀 Quick Query Mode: %s\n", question)
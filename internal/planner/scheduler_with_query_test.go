@@ -0,0 +1,28 @@
+package planner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+func TestQueriesFollowTheScheduler(t *testing.T) {
+	scheduler := NewEnhancedScheduler(calendar.NewMemoryBackend(), models.AIConfig{}, models.CalendarConfig{
+		TimeZone:    "UTC",
+		JournalPath: filepath.Join(t.TempDir(), "journal.json"),
+	})
+	if scheduler.GetQueryService().GetClient().GetJournal() != scheduler.GetCalendarClient().GetJournal() {
+		t.Error("queries should read through the scheduler's journaled client")
+	}
+
+	scheduler, err := scheduler.WithCalendar("work").WithTimeZone("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := scheduler.GetQueryService().GetClient()
+	if client.GetCalendarID() != "work" || client.Location().String() != "Asia/Tokyo" {
+		t.Errorf("queries read %s in %s, want work in Asia/Tokyo", client.GetCalendarID(), client.Location())
+	}
+}
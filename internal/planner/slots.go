@@ -0,0 +1,367 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// slotGranularity is what suggested start times are rounded up to
+const slotGranularity = 30 * time.Minute
+
+// slotSearchStep is how far apart the start times FindSlots considers are
+const slotSearchStep = 15 * time.Minute
+
+// slotSearchDays is how many days are searched when a request names no day
+const slotSearchDays = 7
+
+// partsOfDay are the hours a rough time of day covers, checked in order
+var partsOfDay = []struct {
+	name       string
+	start, end int
+}{
+	{"after lunch", 13, 17},
+	{"morning", 9, 12},
+	{"afternoon", 12, 17},
+	{"evening", 17, 21},
+	{"tonight", 17, 21},
+}
+
+// SlotQuery describes a free slot to look for
+type SlotQuery struct {
+	Day      time.Time // first day searched, at midnight
+	Days     int       // number of days searched, starting with Day
+	Duration time.Duration
+	Window   string // part of the day, e.g. "afternoon"; empty for any time
+	// Other people's busy times, e.g. attendees', that slots avoid too
+	Busy []models.Task
+}
+
+// window returns the part of day the query is limited to
+func (q SlotQuery) window(day time.Time) (time.Time, time.Time) {
+	for _, part := range partsOfDay {
+		if part.name == q.Window {
+			return atHour(day, part.start), atHour(day, part.end)
+		}
+	}
+	return day, day.AddDate(0, 0, 1)
+}
+
+// String describes where the query looks, e.g. "on Sat Oct 18 (afternoon, 12:00-17:00)"
+func (q SlotQuery) String() string {
+	where := fmt.Sprintf("in the next %d days", q.Days)
+	if q.Days == 1 {
+		where = "on " + q.Day.Format("Mon Jan 2")
+	}
+	if q.Window != "" {
+		start, end := q.window(q.Day)
+		where += fmt.Sprintf(" (%s, %s-%s)", q.Window, start.Format("15:04"), end.Format("15:04"))
+	}
+	return where
+}
+
+// ParseSlotQuery reads a slot query from a request that says how long
+// something takes but not when, like "find 45 minutes for a code review
+// tomorrow afternoon", or that names people to meet but no time, like "a
+// meeting with Priya and Alex tomorrow". The latter has no Duration; the
+// caller uses the planned event's. It returns false for any other request.
+func (es *EnhancedScheduler) ParseSlotQuery(question string) (SlotQuery, bool) {
+	slots := es.GetIntentClassifier().ClassifyWithRules(question).Slots
+	if slots.Time != "" || (slots.Duration == "" && len(slots.Participants) == 0) {
+		return SlotQuery{}, false
+	}
+	var duration time.Duration
+	if slots.Duration != "" {
+		var err error
+		duration, err = time.ParseDuration(slots.Duration)
+		if err != nil || duration <= 0 {
+			return SlotQuery{}, false
+		}
+	}
+
+	location := es.GetLocation()
+	query := SlotQuery{Day: atHour(time.Now().In(location), 0), Days: slotSearchDays, Duration: duration}
+	if slots.Date != "" {
+		if day, err := time.ParseInLocation("2006-01-02", slots.Date, location); err == nil {
+			query.Day = day
+			query.Days = 1
+		}
+	}
+
+	lower := strings.ToLower(question)
+	for _, part := range partsOfDay {
+		if strings.Contains(lower, part.name) {
+			query.Window = part.name
+			break
+		}
+	}
+	return query, true
+}
+
+// slotCandidate is a free slot FindSlots can choose
+type slotCandidate struct {
+	slot    models.TimeSlot
+	day     int           // days after the query's first day
+	outside time.Duration // how much of it falls outside the preferred hours
+}
+
+// FindSlots returns the best free slot for a query followed by up to
+// count-1 alternatives that overlap neither it nor each other. Slots keep
+// the configured buffer clear around other events, and are ranked by day,
+// then by how much of them falls outside the preferred hours, then by
+// start. No slots means nothing fits.
+func (es *EnhancedScheduler) FindSlots(query SlotQuery, count int) ([]models.TimeSlot, error) {
+	candidates, err := es.slotCandidates(query)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].day != candidates[j].day {
+			return candidates[i].day < candidates[j].day
+		}
+		if candidates[i].outside != candidates[j].outside {
+			return candidates[i].outside < candidates[j].outside
+		}
+		return candidates[i].slot.Start.Before(candidates[j].slot.Start)
+	})
+
+	var slots []models.TimeSlot
+	for _, c := range candidates {
+		slots = append(slots, c.slot)
+	}
+	return pickSeparate(slots, count), nil
+}
+
+// slotCandidates returns every free slot for a query that starts on a
+// multiple of slotSearchStep, in time order. Times in the past are left out.
+func (es *EnhancedScheduler) slotCandidates(query SlotQuery) ([]slotCandidate, error) {
+	location := es.GetLocation()
+	now := time.Now().In(location)
+	buffer := time.Duration(es.slotPreferences.BufferMinutes) * time.Minute
+
+	var candidates []slotCandidate
+	for day := 0; day < query.Days; day++ {
+		date := query.Day.AddDate(0, 0, day)
+		freeSlots, err := es.GetQueryService().GetCommonFreeTimeSlots(date, query.Duration, buffer, query.Busy)
+		if err != nil {
+			return nil, err
+		}
+
+		windowStart, windowEnd := query.window(date)
+		preferredStart, preferredEnd, hasPreferred := es.preferredHours(date)
+		for _, free := range freeSlots {
+			start := free.Start.In(location)
+			for _, bound := range []time.Time{windowStart, now} {
+				if start.Before(bound) {
+					start = bound
+				}
+			}
+			end := free.End
+			if windowEnd.Before(end) {
+				end = windowEnd
+			}
+
+			for t := roundUp(start, slotSearchStep); !t.Add(query.Duration).After(end); t = t.Add(slotSearchStep) {
+				c := slotCandidate{slot: models.TimeSlot{Start: t, End: t.Add(query.Duration)}, day: day}
+				if hasPreferred {
+					c.outside = query.Duration - overlap(c.slot, preferredStart, preferredEnd)
+				}
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// pickSeparate returns up to count of slots, in order, skipping any that
+// overlap one already picked
+func pickSeparate(slots []models.TimeSlot, count int) []models.TimeSlot {
+	var picked []models.TimeSlot
+	for _, slot := range slots {
+		if len(picked) == count {
+			break
+		}
+		separate := true
+		for _, other := range picked {
+			if overlap(slot, other.Start, other.End) > 0 {
+				separate = false
+				break
+			}
+		}
+		if separate {
+			picked = append(picked, slot)
+		}
+	}
+	return picked
+}
+
+// ProposeAlternatives returns up to count free times, on the same day and
+// with the same length, for a task that overlaps busy. Candidates come from
+// the conflict checker's alternative time and the free-slot search; the
+// ones closest to the requested time come first, and none overlap another.
+func (es *EnhancedScheduler) ProposeAlternatives(task models.Task, busy []models.Task, count int) ([]models.Task, error) {
+	parsed, err := task.ParseTime()
+	if err != nil {
+		return nil, err
+	}
+	location := es.GetLocation()
+	requested := parsed.StartTime.In(location)
+	duration := parsed.EndTime.Sub(parsed.StartTime)
+
+	var slots []models.TimeSlot
+	if resolved := es.conflictChecker.ResolveConflicts([]models.Task{task}, busy); len(resolved) == 1 {
+		if start, err := time.Parse(time.RFC3339, resolved[0].Start); err == nil && !start.Equal(requested) {
+			slots = append(slots, models.TimeSlot{Start: start.In(location), End: start.Add(duration).In(location)})
+		}
+	}
+	candidates, err := es.slotCandidates(SlotQuery{Day: atHour(requested, 0), Days: 1, Duration: duration})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range candidates {
+		slots = append(slots, c.slot)
+	}
+
+	distance := func(t time.Time) time.Duration {
+		if t.Before(requested) {
+			return requested.Sub(t)
+		}
+		return t.Sub(requested)
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return distance(slots[i].Start) < distance(slots[j].Start)
+	})
+
+	// Keep the free ones that are still ahead
+	now := time.Now()
+	var free []models.TimeSlot
+	for _, slot := range slots {
+		alternative := task
+		alternative.Start = slot.Start.Format(time.RFC3339)
+		alternative.End = slot.End.Format(time.RFC3339)
+		if slot.Start.After(now) && !es.conflictChecker.HasTimeConflict(alternative, busy) {
+			free = append(free, slot)
+		}
+	}
+
+	var alternatives []models.Task
+	for _, slot := range pickSeparate(free, count) {
+		alternative := task
+		alternative.Start = slot.Start.Format(time.RFC3339)
+		alternative.End = slot.End.Format(time.RFC3339)
+		alternatives = append(alternatives, alternative)
+	}
+	return alternatives, nil
+}
+
+// preferredHours returns the configured preferred hours on a day, if any
+func (es *EnhancedScheduler) preferredHours(day time.Time) (time.Time, time.Time, bool) {
+	start, err := time.Parse("15:04", es.slotPreferences.PreferredStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse("15:04", es.slotPreferences.PreferredEnd)
+	if err != nil || !end.After(start) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	at := func(clock time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	}
+	return at(start), at(end), true
+}
+
+// overlap returns how much of slot falls between start and end
+func overlap(slot models.TimeSlot, start, end time.Time) time.Duration {
+	if slot.Start.After(start) {
+		start = slot.Start
+	}
+	if slot.End.Before(end) {
+		end = slot.End
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// atHour returns the given hour on t's day, in t's timezone
+func atHour(t time.Time, hour int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
+}
+
+// SuggestSlots returns up to count free times for task on the day it was
+// planned for, keeping its length. The first suggestion in each free period
+// comes before later times in the same period, so suggestions are spread
+// over the day. Times in the past are never suggested.
+func (es *EnhancedScheduler) SuggestSlots(task models.Task, count int) ([]models.Task, error) {
+	start, err := time.Parse(time.RFC3339, task.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time %q: %v", task.Start, err)
+	}
+	duration := time.Hour
+	if end, err := time.Parse(time.RFC3339, task.End); err == nil && end.After(start) {
+		duration = end.Sub(start)
+	}
+
+	location := es.GetLocation()
+	freeSlots, err := es.GetQueryService().GetFreeTimeSlots(start.In(location), duration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Candidate starts per free period
+	now := time.Now().In(location)
+	var periods [][]time.Time
+	for _, slot := range freeSlots {
+		first := slot.Start
+		if first.Before(now) {
+			first = now
+		}
+		first = roundUp(first, slotGranularity)
+
+		var starts []time.Time
+		for t := first; !t.Add(duration).After(slot.End); t = t.Add(duration) {
+			starts = append(starts, t)
+		}
+		if len(starts) > 0 {
+			periods = append(periods, starts)
+		}
+	}
+
+	var suggestions []models.Task
+	for round := 0; len(suggestions) < count; round++ {
+		added := false
+		for _, starts := range periods {
+			if round < len(starts) && len(suggestions) < count {
+				suggestion := task
+				suggestion.Start = starts[round].In(location).Format(time.RFC3339)
+				suggestion.End = starts[round].Add(duration).In(location).Format(time.RFC3339)
+				suggestions = append(suggestions, suggestion)
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Start < suggestions[j].Start // same timezone, so text order is time order
+	})
+	return suggestions, nil
+}
+
+// roundUp rounds t up to a multiple of step in its own timezone
+func roundUp(t time.Time, step time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if remainder := offset % step; remainder != 0 {
+		offset += step - remainder
+	}
+	return midnight.Add(offset)
+}
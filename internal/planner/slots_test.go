@@ -0,0 +1,147 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/calendar"
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// newSlotScheduler returns a scheduler in UTC on a memory calendar holding
+// a 9:00 standup and an 11:00 review tomorrow, which it also returns. The
+// default availability profile books 9:00 to 18:00.
+func newSlotScheduler(t *testing.T, preferences models.SlotPreferences) (*EnhancedScheduler, time.Time) {
+	t.Helper()
+	tomorrow := atHour(time.Now().UTC().AddDate(0, 0, 1), 0)
+
+	backend := calendar.NewMemoryBackend()
+	for _, event := range []models.Task{
+		slotEvent("Standup", tomorrow, "09:00", "10:00"),
+		slotEvent("Design review", tomorrow, "11:00", "12:30"),
+	} {
+		if _, err := backend.InsertEvent(calendar.DefaultCalendarID, event, time.UTC); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scheduler := NewEnhancedScheduler(backend, models.AIConfig{}, models.CalendarConfig{TimeZone: "UTC", SlotPreferences: preferences})
+	return scheduler, tomorrow
+}
+
+// slotEvent returns a task on day between two "15:04" times
+func slotEvent(summary string, day time.Time, start, end string) models.Task {
+	at := func(clock string) string {
+		parsed, _ := time.Parse("15:04", clock)
+		return day.Add(time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute).Format(time.RFC3339)
+	}
+	return models.Task{Summary: summary, Start: at(start), End: at(end)}
+}
+
+// clockTimes formats the starts of slots as "15:04"
+func clockTimes(starts []time.Time) []string {
+	var clocks []string
+	for _, start := range starts {
+		clocks = append(clocks, start.Format("15:04"))
+	}
+	return clocks
+}
+
+func TestFindSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences models.SlotPreferences
+		window      string
+		busy        []string // other people's busy times tomorrow, as start-end pairs
+		want        []string
+	}{
+		{name: "earliest separate slots", want: []string{"10:00", "12:30", "13:30"}},
+		{name: "buffer around events", preferences: models.SlotPreferences{BufferMinutes: 15}, want: []string{"12:45", "13:45", "14:45"}},
+		{name: "preferred hours first", preferences: models.SlotPreferences{PreferredStart: "14:00", PreferredEnd: "17:00"}, want: []string{"14:00", "15:00", "16:00"}},
+		{name: "part of the day", window: "morning", want: []string{"10:00"}},
+		{name: "attendees' busy times", busy: []string{"12:30", "14:00"}, want: []string{"10:00", "14:00", "15:00"}},
+	}
+
+	for _, test := range tests {
+		scheduler, tomorrow := newSlotScheduler(t, test.preferences)
+		query := SlotQuery{Day: tomorrow, Days: 1, Duration: time.Hour, Window: test.window}
+		for i := 0; i+1 < len(test.busy); i += 2 {
+			query.Busy = append(query.Busy, slotEvent("Priya's busy time", tomorrow, test.busy[i], test.busy[i+1]))
+		}
+
+		slots, err := scheduler.FindSlots(query, 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		var starts []time.Time
+		for _, slot := range slots {
+			if slot.End.Sub(slot.Start) != time.Hour {
+				t.Errorf("%s: slot %s-%s is not an hour long", test.name, slot.Start, slot.End)
+			}
+			starts = append(starts, slot.Start)
+		}
+		if got := clockTimes(starts); !equalClocks(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindSlotsNothingFits(t *testing.T) {
+	scheduler, tomorrow := newSlotScheduler(t, models.SlotPreferences{})
+
+	slots, err := scheduler.FindSlots(SlotQuery{Day: tomorrow, Days: 1, Duration: 10 * time.Hour}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 0 {
+		t.Errorf("a 10 hour slot shouldn't fit in a 9 hour working day, got %v", slots)
+	}
+}
+
+func equalClocks(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestProposeAlternatives(t *testing.T) {
+	scheduler, tomorrow := newSlotScheduler(t, models.SlotPreferences{})
+	busy, err := scheduler.GetCalendarClient().ListEvents(tomorrow, tomorrow.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interview := slotEvent("Interview", tomorrow, "11:30", "12:30")
+
+	alternatives, err := scheduler.ProposeAlternatives(interview, busy, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var starts []time.Time
+	for _, alternative := range alternatives {
+		parsed, err := alternative.ParseTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if alternative.Summary != "Interview" || parsed.EndTime.Sub(parsed.StartTime) != time.Hour {
+			t.Errorf("alternative %+v should keep the event and its length", alternative)
+		}
+		if scheduler.GetConflictChecker().HasTimeConflict(alternative, busy) {
+			t.Errorf("alternative at %s conflicts", alternative.Start)
+		}
+		starts = append(starts, parsed.StartTime.UTC())
+	}
+
+	// Closest to the requested 11:30 first, none overlapping another
+	if got, want := clockTimes(starts), []string{"12:30", "10:00", "13:30"}; !equalClocks(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
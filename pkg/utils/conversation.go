@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// FormatConversation describes the earlier turns of a session for a prompt,
+// so follow-ups like "make it 30 minutes instead" can be resolved. It returns
+// an empty string when there is nothing to describe.
+func FormatConversation(conversation *models.Conversation) string {
+	if conversation == nil || (len(conversation.History) == 0 && conversation.Clarification == nil) {
+		return ""
+	}
+
+	var text strings.Builder
+	if len(conversation.History) > 0 {
+		text.WriteString("CONVERSATION SO FAR (oldest first):\n")
+		for _, turn := range conversation.History {
+			role := "User"
+			if turn.Role == "assistant" {
+				role = "Assistant"
+			}
+			text.WriteString(fmt.Sprintf("%s: %s\n", role, oneLine(turn.Content)))
+		}
+	}
+
+	if len(conversation.ReferencedEvents) > 0 {
+		text.WriteString("\nEvents the conversation was last about:\n")
+		for _, event := range conversation.ReferencedEvents {
+			if event.EventID != "" {
+				text.WriteString(fmt.Sprintf("- [id: %s] %s (%s)\n", event.EventID, event.Summary, FormatDateTime(event.Start)))
+			} else {
+				text.WriteString(fmt.Sprintf("- %s (%s)\n", event.Summary, FormatDateTime(event.Start)))
+			}
+		}
+	}
+
+	if clarification := conversation.Clarification; clarification != nil {
+		text.WriteString(fmt.Sprintf("\nThe assistant asked %q about the request %q. The user request below answers that question.\n",
+			oneLine(clarification.Question), clarification.Request))
+		for _, option := range clarification.Options {
+			text.WriteString(fmt.Sprintf("  Option %s: %s\n", option.ID, option.Label))
+		}
+	}
+
+	text.WriteString("\nResolve references like \"it\", \"that one\" or \"the other one\" using the conversation.\n")
+	return text.String()
+}
+
+// oneLine collapses a message onto one line
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
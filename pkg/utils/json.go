@@ -10,17 +10,7 @@ import (
 
 // ParsePlan parses AI-generated plan JSON into tasks
 func ParsePlan(planJSON string) ([]models.Task, error) {
-	// Clean up the JSON if it's wrapped in markdown code blocks
-	planJSON = strings.TrimSpace(planJSON)
-	if strings.HasPrefix(planJSON, "```json") {
-		planJSON = strings.TrimPrefix(planJSON, "```json")
-		planJSON = strings.TrimSuffix(planJSON, "```")
-		planJSON = strings.TrimSpace(planJSON)
-	} else if strings.HasPrefix(planJSON, "```") {
-		planJSON = strings.TrimPrefix(planJSON, "```")
-		planJSON = strings.TrimSuffix(planJSON, "```")
-		planJSON = strings.TrimSpace(planJSON)
-	}
+	planJSON = StripCodeFence(planJSON)
 
 	var tasks []models.Task
 	if err := json.Unmarshal([]byte(planJSON), &tasks); err != nil {
@@ -36,3 +26,38 @@ func ParsePlan(planJSON string) ([]models.Task, error) {
 
 	return tasks, nil
 }
+
+// ParseEventChange parses an AI-generated JSON object describing a change to
+// an existing event. Empty fields mean "keep the current value".
+func ParseEventChange(changeJSON string) (models.Task, error) {
+	var change models.Task
+	if err := json.Unmarshal([]byte(StripCodeFence(changeJSON)), &change); err != nil {
+		return change, fmt.Errorf("failed to parse event change JSON: %v", err)
+	}
+
+	if change.EventID == "" {
+		return change, fmt.Errorf("event change has no event_id")
+	}
+	for _, reminder := range change.Reminders {
+		if err := reminder.Validate(); err != nil {
+			return change, fmt.Errorf("event change has an invalid reminder: %v", err)
+		}
+	}
+
+	return change, nil
+}
+
+// StripCodeFence removes a markdown code block wrapped around JSON
+func StripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```json") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimSuffix(text, "```")
+		text = strings.TrimSpace(text)
+	} else if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(text, "```")
+		text = strings.TrimSpace(text)
+	}
+	return text
+}
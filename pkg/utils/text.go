@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Karan2980/llm-planner-golang-project/internal/models"
+)
+
+// JoinNames lists names in a sentence, e.g. "Priya, Alex and Sam"
+func JoinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// DescribeReminders lists reminders in a sentence, e.g. "a popup 30 minutes
+// before and an email 1 day before"
+func DescribeReminders(reminders []models.Reminder) string {
+	var parts []string
+	for _, reminder := range reminders {
+		what := "a popup"
+		if reminder.Method == models.ReminderEmail {
+			what = "an email"
+		}
+		when := "when it starts"
+		if reminder.Minutes > 0 {
+			when = describeMinutes(reminder.Minutes) + " before"
+		}
+		parts = append(parts, what+" "+when)
+	}
+	return JoinNames(parts)
+}
+
+// ReminderNote describes an event's own reminders for a list of events,
+// e.g. " - reminders: a popup 30 minutes before"; empty if it has none
+func ReminderNote(event models.Task) string {
+	if len(event.Reminders) == 0 {
+		return ""
+	}
+	return " - reminders: " + DescribeReminders(event.Reminders)
+}
+
+// describeMinutes spells out a number of minutes in the largest whole unit,
+// e.g. "2 hours" or "1 day"
+func describeMinutes(minutes int) string {
+	for _, unit := range []struct {
+		name    string
+		minutes int
+	}{
+		{"week", 7 * 24 * 60},
+		{"day", 24 * 60},
+		{"hour", 60},
+	} {
+		if minutes%unit.minutes == 0 {
+			return plural(minutes/unit.minutes, unit.name)
+		}
+	}
+	return plural(minutes, "minute")
+}
+
+// plural writes a count with its unit, e.g. "1 day" or "3 days"
+func plural(count int, unit string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}